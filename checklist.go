@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wltechblog/notes/internal/checklist"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+// syncChecklistToTasks completes the tasks whose checklist items were ticked
+// in note and reopens those whose items were cleared. Tasks whose item moved
+// to another line are re-pointed at it.
+func syncChecklistToTasks(note *notes.Note) error {
+	tm, err := tasks.NewTaskManager()
+	if err != nil {
+		return err
+	}
+
+	linked, err := tm.ListTasks(tasks.Filter{NoteID: note.ID})
+	if err != nil {
+		return err
+	}
+
+	workflow := tasks.CurrentWorkflow()
+	items := checklist.Parse(note.Content)
+	for _, task := range linked {
+		if task.NoteLine == 0 {
+			continue
+		}
+		item, err := checklist.Find(items, task.NoteLine, task.Name)
+		if errors.Is(err, checklist.ErrAmbiguous) {
+			notice("Warning: cannot tell which checklist item belongs to task %s: %v\n", task.ID, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if item.Line != task.NoteLine {
+			if _, err := tm.SetNote(task.ID, note.ID, item.Line); err != nil {
+				return err
+			}
+		}
+
+		var status tasks.Status
+		switch {
+		case item.Checked && !task.IsTerminal():
+			status = workflow.Completed()
+		case !item.Checked && task.Status == workflow.Completed():
+			status = workflow.Initial()
+		default:
+			continue
+		}
+
+		update, err := tm.UpdateTaskStatus(task.ID, status, fmt.Sprintf("checklist item in note %s", note.ID))
+		if err != nil {
+			notice("Warning: could not update task %s: %v\n", task.ID, err)
+			continue
+		}
+		handleStatusUpdate(update)
+	}
+	return nil
+}
+
+// syncTaskToChecklist ticks the checklist item a task was extracted from when
+// the task is completed, and clears it when the task is reopened.
+func syncTaskToChecklist(task *tasks.Task) error {
+	if task.NoteID == "" || task.NoteLine == 0 {
+		return nil
+	}
+
+	workflow := tasks.CurrentWorkflow()
+	var checked bool
+	switch {
+	case task.Status == workflow.Completed():
+		checked = true
+	case !task.IsTerminal():
+		checked = false
+	default:
+		return nil
+	}
+
+	nm, err := notes.NewNoteManager()
+	if err != nil {
+		return err
+	}
+	note, err := nm.GetNote(task.NoteID)
+	if err != nil {
+		return nil
+	}
+
+	item, err := checklist.Find(checklist.Parse(note.Content), task.NoteLine, task.Name)
+	if errors.Is(err, checklist.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	content, changed := checklist.SetChecked(note.Content, item.Line, checked)
+	if !changed {
+		return nil
+	}
+
+	_, err = nm.UpdateNote(note.ID, content)
+	return err
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/tasks"
+	"github.com/wltechblog/notes/internal/templates"
+)
+
+const timestampLayout = "2006-01-02 15:04:05"
+
+// previewLength is how many characters of content lists show.
+const previewLength = 30
+
+var noteColumns = map[string]func(note *notes.Note) string{
+	"id":      func(n *notes.Note) string { return n.ID },
+	"name":    func(n *notes.Note) string { return n.Name },
+	"tags":    func(n *notes.Note) string { return strings.Join(n.Tags, ",") },
+	"created": func(n *notes.Note) string { return n.CreatedAt.Format(timestampLayout) },
+	"updated": func(n *notes.Note) string { return n.UpdatedAt.Format(timestampLayout) },
+	"content": func(n *notes.Note) string { return templates.Truncate(previewLength, n.Content) },
+}
+
+var taskColumns = map[string]func(task *tasks.Task, now time.Time) string{
+	"id":       func(t *tasks.Task, now time.Time) string { return t.ID },
+	"name":     func(t *tasks.Task, now time.Time) string { return t.Name },
+	"status":   func(t *tasks.Task, now time.Time) string { return string(t.Status) },
+	"tags":     func(t *tasks.Task, now time.Time) string { return strings.Join(t.Tags, ",") },
+	"project":  func(t *tasks.Task, now time.Time) string { return t.Project },
+	"priority": func(t *tasks.Task, now time.Time) string { return string(t.Priority) },
+	"due":      func(t *tasks.Task, now time.Time) string { return formatOptionalDate(t.DueAt) },
+	"scheduled": func(t *tasks.Task, now time.Time) string {
+		return formatOptionalDate(t.ScheduledAt)
+	},
+	"urgency": func(t *tasks.Task, now time.Time) string { return fmt.Sprintf("%.2f", t.Urgency(now)) },
+	"tracked": func(t *tasks.Task, now time.Time) string { return formatTracked(t, now) },
+	"note":    func(t *tasks.Task, now time.Time) string { return t.NoteID },
+	"created": func(t *tasks.Task, now time.Time) string { return t.CreatedAt.Format(timestampLayout) },
+	"updated": func(t *tasks.Task, now time.Time) string { return t.UpdatedAt.Format(timestampLayout) },
+	"content": func(t *tasks.Task, now time.Time) string { return templates.Truncate(previewLength, t.Content) },
+}
+
+// parseColumns splits a comma-separated column list and checks every name
+// against the available columns.
+func parseColumns[T any](value string, available map[string]T) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := available[column]; !ok {
+			return nil, fmt.Errorf("unknown column: %s (must be one of: %s)", column, strings.Join(columnNames(available), ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func columnNames[T any](available map[string]T) []string {
+	names := make([]string, 0, len(available))
+	for name := range available {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatNoteColumns(note *notes.Note, columns []string) string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = noteColumns[column](note)
+	}
+	return strings.Join(values, " | ")
+}
+
+func formatTaskColumns(task *tasks.Task, columns []string, now time.Time) string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = taskColumns[column](task, now)
+	}
+	return strings.Join(values, " | ")
+}
+
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatDate(*t)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/wltechblog/notes/internal/config"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var appConfig, configErr = loadConfig()
+
+// loadConfig reads the user configuration and installs the task workflow it
+// defines. It runs before any command is registered so that help text and
+// completions reflect the configured statuses.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return &config.Config{}, err
+	}
+
+	workflow, err := tasks.NewWorkflow(cfg.Workflow)
+	if err != nil {
+		path, _ := config.Path()
+		return cfg, fmt.Errorf("invalid workflow in %s: %w", path, err)
+	}
+	tasks.UseWorkflow(workflow)
+
+	return cfg, nil
+}
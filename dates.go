@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wltechblog/notes/internal/dateparse"
+)
+
+func parseDate(s string) (time.Time, error) {
+	return dateparse.Parse(s, time.Now())
+}
+
+// parseOptionalDate is parseDate for arguments where "none" clears the date.
+func parseOptionalDate(s string) (*time.Time, error) {
+	if s == "none" {
+		return nil, nil
+	}
+	t, err := parseDate(s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func formatDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// formatDuration renders d rounded to the minute, e.g. "1h30m" or "45m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh%dm", hours, minutes)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
 )
 
 var deleteCmd = &cobra.Command{
@@ -21,11 +23,18 @@ var deleteCmd = &cobra.Command{
 		}
 
 		id := args[0]
+		note, err := nm.GetNote(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+		}
 		if err := nm.DeleteNote(id); err != nil {
-			fmt.Printf("Failed to delete note: %v\n", err)
-			return nil
+			return err
 		}
 
+		if machineOutput() {
+			deleted := []deletion{{Type: "note", ID: id, Name: note.Name}}
+			return output.List(os.Stdout, outputFormat, deleted, deletionColumns)
+		}
 		fmt.Printf("Note deleted: %s\n", id)
 		return nil
 	},
@@ -33,6 +42,6 @@ var deleteCmd = &cobra.Command{
 
 func init() {
 	if noteMode {
-		rootCmd.AddCommand(deleteCmd)
+		rootCmd.AddCommand(supportsOutput(deleteCmd))
 	}
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/diff"
+	"github.com/wltechblog/notes/internal/notes"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [id] [revA] [revB]",
+	Short: "Show changes between revisions of a note",
+	Long: "Show changes between revisions of a note. With no revisions, compares the latest " +
+		"revision with the current note; with one, compares that revision with the current note",
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task diff' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		current, err := nm.GetNote(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+		}
+
+		var revA int
+		if len(args) > 1 {
+			if revA, err = parseRevision(args[1]); err != nil {
+				return err
+			}
+		} else {
+			revisions, err := nm.History(id)
+			if err != nil {
+				return err
+			}
+			if len(revisions) == 0 {
+				fmt.Printf("Note %s has no earlier revisions\n", id)
+				return nil
+			}
+			revA = revisions[len(revisions)-1].Number
+		}
+
+		a, err := nm.GetRevision(id, revA)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Revision %d of note %s not found", revA, id)
+		}
+		aLabel := fmt.Sprintf("note %s revision %d", id, revA)
+
+		b, bLabel := current, fmt.Sprintf("note %s (current)", id)
+		if len(args) > 2 {
+			revB, err := parseRevision(args[2])
+			if err != nil {
+				return err
+			}
+			if b, err = nm.GetRevision(id, revB); err != nil {
+				return exitWith(cmd, exitNotFound, "Revision %d of note %s not found", revB, id)
+			}
+			bLabel = fmt.Sprintf("note %s revision %d", id, revB)
+		}
+
+		out := diff.Unified(noteDiffText(a), noteDiffText(b), aLabel, bLabel, 3)
+		if out == "" {
+			fmt.Println("No differences")
+			return nil
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+func noteDiffText(note *notes.Note) string {
+	return fmt.Sprintf("Name: %s\nTags: %s\n\n%s", note.Name, strings.Join(note.Tags, ", "), note.Content)
+}
+
+func init() {
+	if noteMode {
+		rootCmd.AddCommand(diffCmd)
+	}
+}
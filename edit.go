@@ -23,8 +23,7 @@ var editCmd = &cobra.Command{
 		id := args[0]
 		note, err := nm.GetNote(id)
 		if err != nil {
-			fmt.Printf("Note not found: %s\n", id)
-			return nil
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
 		}
 
 		if err := nm.EditInEditor(note); err != nil {
@@ -37,7 +36,7 @@ var editCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Note updated: %s\n", id)
-		return nil
+		return syncChecklistToTasks(note)
 	},
 }
 
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/checklist"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var extractTasksCmd = &cobra.Command{
+	Use:   "extract-tasks [id]",
+	Short: "Create tasks from a note's unchecked checklist items",
+	Long: "Create a task for every unchecked Markdown checkbox ('- [ ] ...') in a note. " +
+		"Each task is linked back to the note and its line. Completing the task ticks the box, and " +
+		"ticking the box while editing the note completes the task. Items that already have a task are skipped",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		note, err := nm.GetNote(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+		}
+
+		linked, err := tm.ListTasks(tasks.Filter{NoteID: id})
+		if err != nil {
+			return err
+		}
+		items := checklist.Parse(note.Content)
+		extracted := make(map[int]bool)
+		for _, task := range linked {
+			if task.NoteLine == 0 {
+				continue
+			}
+			item, err := checklist.Find(items, task.NoteLine, task.Name)
+			if errors.Is(err, checklist.ErrAmbiguous) {
+				// Any of the items could be this task's, so leave them all
+				// alone rather than extract one of them twice.
+				notice("Warning: cannot tell which checklist item belongs to task %s: %v\n", task.ID, err)
+				for _, other := range items {
+					if other.Text == task.Name {
+						extracted[other.Line] = true
+					}
+				}
+				continue
+			}
+			if err == nil {
+				extracted[item.Line] = true
+			}
+		}
+
+		created := 0
+		for _, item := range items {
+			if item.Checked || extracted[item.Line] {
+				continue
+			}
+			task := &tasks.Task{Name: item.Text, Tags: note.Tags, NoteID: id, NoteLine: item.Line}
+			if err := tm.Create(task); err != nil {
+				return err
+			}
+			fmt.Printf("Task created: %s | %s\n", task.ID, task.Name)
+			created++
+		}
+
+		if created == 0 {
+			fmt.Println("No new checklist items found")
+		}
+		return nil
+	},
+}
+
+func init() {
+	if noteMode {
+		rootCmd.AddCommand(extractTasksCmd)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [id]",
+	Short: "List the saved revisions of a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task history' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		note, err := nm.GetNote(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+		}
+
+		revisions, err := nm.History(id)
+		if err != nil {
+			return err
+		}
+
+		for _, rev := range revisions {
+			fmt.Printf("%d | %s | Updated: %s\n",
+				rev.Number,
+				rev.Note.Name,
+				rev.Note.UpdatedAt.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Printf("current | %s | Updated: %s\n",
+			note.Name,
+			note.UpdatedAt.Format("2006-01-02 15:04:05"))
+
+		return nil
+	},
+}
+
+func parseRevision(arg string) (int, error) {
+	rev, err := strconv.Atoi(arg)
+	if err != nil || rev < 1 {
+		return 0, fmt.Errorf("invalid revision: %s (must be a positive number)", arg)
+	}
+	return rev, nil
+}
+
+func init() {
+	if noteMode {
+		rootCmd.AddCommand(historyCmd)
+	}
+}
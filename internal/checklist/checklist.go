@@ -0,0 +1,87 @@
+package checklist
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Item is a Markdown checkbox line such as "- [ ] follow up with ops".
+type Item struct {
+	Line    int
+	Checked bool
+	Text    string
+}
+
+var itemPattern = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.*?)\s*$`)
+
+// Parse returns the checkbox items in content with their 1-based line
+// numbers.
+func Parse(content string) []Item {
+	var items []Item
+	for i, line := range strings.Split(content, "\n") {
+		m := itemPattern.FindStringSubmatch(line)
+		if m == nil || m[4] == "" {
+			continue
+		}
+		items = append(items, Item{Line: i + 1, Checked: m[2] != " ", Text: m[4]})
+	}
+	return items
+}
+
+var (
+	ErrNotFound  = errors.New("checklist item not found")
+	ErrAmbiguous = errors.New("several checklist items have the same text")
+)
+
+// Find locates the item recorded at line with the given text. If the note
+// was edited and the item moved, the item with the same text is returned
+// instead, provided there is only one; otherwise Find returns ErrAmbiguous
+// rather than guess which of them was meant.
+func Find(items []Item, line int, text string) (Item, error) {
+	var moved []Item
+	for _, item := range items {
+		if item.Text != text {
+			continue
+		}
+		if item.Line == line {
+			return item, nil
+		}
+		moved = append(moved, item)
+	}
+
+	switch len(moved) {
+	case 0:
+		return Item{}, ErrNotFound
+	case 1:
+		return moved[0], nil
+	}
+	return Item{}, fmt.Errorf("%w: %q", ErrAmbiguous, text)
+}
+
+// SetChecked ticks or clears the checkbox on the given line. It reports
+// whether content changed.
+func SetChecked(content string, line int, checked bool) (string, bool) {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		return content, false
+	}
+
+	m := itemPattern.FindStringSubmatchIndex(lines[line-1])
+	if m == nil {
+		return content, false
+	}
+
+	mark := " "
+	if checked {
+		mark = "x"
+	}
+	current := lines[line-1][m[4]:m[5]]
+	if (current != " ") == checked {
+		return content, false
+	}
+
+	lines[line-1] = lines[line-1][:m[4]] + mark + lines[line-1][m[5]:]
+	return strings.Join(lines, "\n"), true
+}
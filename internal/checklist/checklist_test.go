@@ -0,0 +1,75 @@
+package checklist
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := "# Launch\n- [ ] write docs\n  * [x] book room\nnot an item\n+ [X] send invites  \n- [ ] \n- [] broken"
+	want := []Item{
+		{Line: 2, Checked: false, Text: "write docs"},
+		{Line: 3, Checked: true, Text: "book room"},
+		{Line: 5, Checked: true, Text: "send invites"},
+	}
+	if got := Parse(content); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse = %+v; want %+v", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	items := []Item{
+		{Line: 2, Text: "call bob"},
+		{Line: 3, Text: "write docs"},
+		{Line: 5, Text: "call bob"},
+		{Line: 7, Text: "book room"},
+	}
+
+	tests := []struct {
+		name string
+		line int
+		text string
+		want int
+		err  error
+	}{
+		{"exact", 3, "write docs", 3, nil},
+		{"exact duplicate", 5, "call bob", 5, nil},
+		{"moved", 4, "book room", 7, nil},
+		{"moved duplicate", 4, "call bob", 0, ErrAmbiguous},
+		{"renamed", 3, "write the docs", 0, ErrNotFound},
+		{"missing", 9, "water plants", 0, ErrNotFound},
+	}
+	for _, tt := range tests {
+		item, err := Find(items, tt.line, tt.text)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: Find error = %v; want %v", tt.name, err, tt.err)
+			continue
+		}
+		if item.Line != tt.want {
+			t.Errorf("%s: Find = line %d; want %d", tt.name, item.Line, tt.want)
+		}
+	}
+}
+
+func TestSetChecked(t *testing.T) {
+	content := "todo\n- [ ] write docs\n* [x] book room"
+	tests := []struct {
+		line    int
+		checked bool
+		want    string
+		changed bool
+	}{
+		{2, true, "todo\n- [x] write docs\n* [x] book room", true},
+		{3, false, "todo\n- [ ] write docs\n* [ ] book room", true},
+		{3, true, content, false},
+		{1, true, content, false},
+		{9, true, content, false},
+	}
+	for _, tt := range tests {
+		got, changed := SetChecked(content, tt.line, tt.checked)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("SetChecked(line %d, %v) = %q, %v; want %q, %v", tt.line, tt.checked, got, changed, tt.want, tt.changed)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/wltechblog/notes/internal/platform"
+)
+
+const fileName = "config.json"
+
+// Config is the user configuration shared by the note and task commands.
+// Every section is optional; a missing file yields the zero Config.
+type Config struct {
+	Workflow  *Workflow  `json:"workflow,omitempty"`
+	Templates *Templates `json:"templates,omitempty"`
+}
+
+// Workflow describes the task statuses and the transitions allowed between
+// them. Transitions maps a status to the statuses it may move to; a status
+// with no entry may move to any other. Initial is given to new tasks and
+// Completed is the status that counts as done; both default to the first
+// non-terminal and first terminal status respectively.
+type Workflow struct {
+	Statuses    []Status            `json:"statuses"`
+	Transitions map[string][]string `json:"transitions,omitempty"`
+	Initial     string              `json:"initial,omitempty"`
+	Completed   string              `json:"completed,omitempty"`
+}
+
+// Templates holds named output templates for notes and tasks, used by the
+// list and search commands with --format NAME.
+type Templates struct {
+	Notes map[string]string `json:"notes,omitempty"`
+	Tasks map[string]string `json:"tasks,omitempty"`
+}
+
+type Status struct {
+	Name        string `json:"name"`
+	Terminal    bool   `json:"terminal,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func Path() (string, error) {
+	dir, err := platform.GetConfigDir(platform.ConfigSubdir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,271 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var absoluteLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var (
+	relativePattern  = regexp.MustCompile(`^(?:in\s+)?([+-]?\d+)\s*([a-z]+?)s?(\s+ago)?$`)
+	clockPattern     = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+	trailingClockPat = regexp.MustCompile(`^(.*?)\s+(?:at\s+)?(\d{1,2}(?::\d{2})?\s*(?:am|pm)?)$`)
+)
+
+// Parse resolves a date expression relative to now, in now's location.
+// Besides absolute dates (2026-11-01, 2026-11-01 14:00, RFC3339) it
+// understands now, today, tomorrow, yesterday, weekday names (the next
+// occurrence after today, optionally prefixed with "next" or "last"),
+// "next week/month/year", relative offsets ("in 3 days", "2 weeks ago",
+// "+3d", "-7d") and the period anchors sod/eod, sow/eow, som/eom, soy/eoy.
+// Day-level expressions may be followed by a time of day ("tomorrow 14:00",
+// "friday at 9am").
+func Parse(s string, now time.Time) (time.Time, error) {
+	expr := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if expr == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(expr), now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, ok := parseRelative(expr, now); ok {
+		return t, nil
+	}
+
+	if t, ok := parseDay(expr, now); ok {
+		return t, nil
+	}
+
+	if m := trailingClockPat.FindStringSubmatch(expr); m != nil {
+		day, ok := parseDay(m[1], now)
+		if !ok {
+			if d, err := time.ParseInLocation("2006-01-02", m[1], now.Location()); err == nil {
+				day, ok = d, true
+			}
+		}
+		if ok {
+			if t, ok := applyClock(day, m[2]); ok {
+				return t, nil
+			}
+		}
+	}
+
+	if t, ok := applyClock(startOfDay(now), expr); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date: %q", s)
+}
+
+// ParseDuration accepts Go durations (1h30m, 45m) plus day (d) and week (w)
+// units, which may be combined ("1w2d", "2d4h").
+func ParseDuration(s string) (time.Duration, error) {
+	expr := strings.ToLower(strings.TrimSpace(s))
+	if expr == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	rest := expr
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		idx := strings.Index(rest, unit.suffix)
+		if idx < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:idx])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		total += time.Duration(n) * unit.size
+		rest = rest[idx+1:]
+	}
+
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		total += d
+	}
+
+	return total, nil
+}
+
+func parseDay(expr string, now time.Time) (time.Time, bool) {
+	today := startOfDay(now)
+
+	switch expr {
+	case "now":
+		return now, true
+	case "today", "sod":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "eod":
+		return endOf(today.AddDate(0, 0, 1)), true
+	case "sow":
+		return startOfWeek(today), true
+	case "eow":
+		return endOf(startOfWeek(today).AddDate(0, 0, 7)), true
+	case "som":
+		return startOfMonth(today), true
+	case "eom":
+		return endOf(startOfMonth(today).AddDate(0, 1, 0)), true
+	case "soy":
+		return time.Date(today.Year(), 1, 1, 0, 0, 0, 0, today.Location()), true
+	case "eoy":
+		return endOf(time.Date(today.Year()+1, 1, 1, 0, 0, 0, 0, today.Location())), true
+	case "next week":
+		return startOfWeek(today).AddDate(0, 0, 7), true
+	case "next month":
+		return startOfMonth(today).AddDate(0, 1, 0), true
+	case "next year":
+		return time.Date(today.Year()+1, 1, 1, 0, 0, 0, 0, today.Location()), true
+	case "last week":
+		return startOfWeek(today).AddDate(0, 0, -7), true
+	case "last month":
+		return startOfMonth(today).AddDate(0, -1, 0), true
+	case "last year":
+		return time.Date(today.Year()-1, 1, 1, 0, 0, 0, 0, today.Location()), true
+	}
+
+	direction := 1
+	name := expr
+	if rest, ok := strings.CutPrefix(expr, "next "); ok {
+		name = rest
+	} else if rest, ok := strings.CutPrefix(expr, "last "); ok {
+		name, direction = rest, -1
+	}
+
+	weekday, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if direction > 0 {
+		if days == 0 {
+			days = 7
+		}
+	} else {
+		days -= 7
+	}
+	return today.AddDate(0, 0, days), true
+}
+
+func parseRelative(expr string, now time.Time) (time.Time, bool) {
+	m := relativePattern.FindStringSubmatch(expr)
+	if m == nil {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	if m[3] != "" {
+		n = -n
+	}
+
+	switch m[2] {
+	case "m", "min", "minute":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "h", "hr", "hour":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "d", "day":
+		return startOfDay(now).AddDate(0, 0, n), true
+	case "w", "wk", "week":
+		return startOfDay(now).AddDate(0, 0, 7*n), true
+	case "mo", "month":
+		return startOfDay(now).AddDate(0, n, 0), true
+	case "y", "yr", "year":
+		return startOfDay(now).AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+func applyClock(day time.Time, clock string) (time.Time, bool) {
+	m := clockPattern.FindStringSubmatch(strings.TrimSpace(clock))
+	if m == nil {
+		return time.Time{}, false
+	}
+	if m[2] == "" && m[3] == "" {
+		return time.Time{}, false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	switch m[3] {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return time.Time{}, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return time.Time{}, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, false
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()), true
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// endOf returns the last second before the start of the given period.
+func endOf(next time.Time) time.Time {
+	return next.Add(-time.Second)
+}
@@ -0,0 +1,98 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d, hour, min, sec int) time.Time {
+		return time.Date(2026, month, d, hour, min, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-11-01", day(11, 1, 0, 0, 0)},
+		{"2026-11-01 14:00", day(11, 1, 14, 0, 0)},
+		{"2026-11-01T14:00:05Z", day(11, 1, 14, 0, 5)},
+		{"2026/11/01", day(11, 1, 0, 0, 0)},
+		{"now", now},
+		{"today", day(10, 14, 0, 0, 0)},
+		{"  Tomorrow ", day(10, 15, 0, 0, 0)},
+		{"yesterday", day(10, 13, 0, 0, 0)},
+		{"friday", day(10, 16, 0, 0, 0)},
+		{"wed", day(10, 21, 0, 0, 0)},
+		{"next friday", day(10, 16, 0, 0, 0)},
+		{"last monday", day(10, 12, 0, 0, 0)},
+		{"last wednesday", day(10, 7, 0, 0, 0)},
+		{"next week", day(10, 19, 0, 0, 0)},
+		{"next month", day(11, 1, 0, 0, 0)},
+		{"last year", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"in 3 days", day(10, 17, 0, 0, 0)},
+		{"2 weeks ago", day(9, 30, 0, 0, 0)},
+		{"+3d", day(10, 17, 0, 0, 0)},
+		{"-7d", day(10, 7, 0, 0, 0)},
+		{"in 2 hours", day(10, 14, 12, 30, 0)},
+		{"+1mo", day(11, 14, 0, 0, 0)},
+		{"eod", day(10, 14, 23, 59, 59)},
+		{"sow", day(10, 12, 0, 0, 0)},
+		{"eow", day(10, 18, 23, 59, 59)},
+		{"eom", day(10, 31, 23, 59, 59)},
+		{"soy", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow 14:00", day(10, 15, 14, 0, 0)},
+		{"friday at 9am", day(10, 16, 9, 0, 0)},
+		{"2026-11-01 at 5pm", day(11, 1, 17, 0, 0)},
+		{"12am", day(10, 14, 0, 0, 0)},
+		{"12pm", day(10, 14, 12, 0, 0)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v; want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	for _, in := range []string{"", "   ", "someday", "next fortnight", "in 3 parsecs", "13pm", "25:00", "friday at noon"} {
+		if got, err := Parse(in, now); err == nil {
+			t.Errorf("Parse(%q) = %v; want an error", in, got)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"45m", 45 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"2d", 48 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"1w2d", 9 * 24 * time.Hour},
+		{"2d4h", 52 * time.Hour},
+		{"0d", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "-2h", "xd", "2d-1h"} {
+		if got, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v; want an error", in, got)
+		}
+	}
+}
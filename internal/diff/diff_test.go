@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// lcsLength is the quadratic reference the edit scripts are checked against.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func checkOps(t *testing.T, a, b []string) {
+	t.Helper()
+	ops := lineOps(a, b)
+
+	var gotA, gotB []string
+	equal := 0
+	for _, o := range ops {
+		if o.kind != opInsert {
+			if o.a != len(gotA) {
+				t.Fatalf("lineOps(%q, %q): op %+v at a index %d", a, b, o, len(gotA))
+			}
+			gotA = append(gotA, o.text)
+		}
+		if o.kind != opDelete {
+			if o.b != len(gotB) {
+				t.Fatalf("lineOps(%q, %q): op %+v at b index %d", a, b, o, len(gotB))
+			}
+			gotB = append(gotB, o.text)
+		}
+		if o.kind == opEqual {
+			equal++
+		}
+	}
+	if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+		t.Fatalf("lineOps(%q, %q) rebuilds %q, %q", a, b, gotA, gotB)
+	}
+	if want := lcsLength(a, b); equal != want {
+		t.Fatalf("lineOps(%q, %q) keeps %d lines; want %d", a, b, equal, want)
+	}
+}
+
+func TestLineOps(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"", ""},
+		{"", "a b"},
+		{"a b", ""},
+		{"a b c", "a b c"},
+		{"a b c", "a x c"},
+		{"a b c a b b a", "c b a b a c"},
+		{"x a b c", "a b c y"},
+		{"a a a b", "b a a a"},
+	}
+	for _, tt := range tests {
+		checkOps(t, strings.Fields(tt.a), strings.Fields(tt.b))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		checkOps(t, random(), random())
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"
+	b := "one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\n"
+	want := `--- a
++++ b
+@@ -1,3 +1,3 @@
+ one
+-two
++2
+ three
+@@ -8 +8,2 @@
+ eight
++nine
+`
+	if got := Unified(a, b, "a", "b", 1); got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+	if got := Unified(a, a, "a", "b", 3); got != "" {
+		t.Errorf("Unified of equal inputs = %q; want empty", got)
+	}
+}
@@ -0,0 +1,232 @@
+// Package fuzzy matches words that differ by a few typos, using edit
+// distance with adjacent swaps counted as one typo.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span is a byte range within a string.
+type Span struct {
+	Start, End int
+}
+
+type word struct {
+	text string
+	Span
+	// parts holds the letters and digits of a word joined by '-', '.' or
+	// '_', such as web, 01 and example in web-01.example.
+	parts []word
+}
+
+// MaxEdits is the number of typos tolerated in a word of n runes: none for
+// very short words, growing with length.
+func MaxEdits(n int) int {
+	switch {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	case n <= 9:
+		return 2
+	}
+	return 3
+}
+
+// Distance returns the edit distance between a and b in runes: the number
+// of insertions, deletions, substitutions and swaps of adjacent runes that
+// turn one into the other.
+func Distance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	rows := make([][]int, len(ar)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(br)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(ar)][len(br)]
+}
+
+// similarity scores how close candidate is to q, from 0 to 1, and reports
+// whether it is within MaxEdits typos of it. Both must be lower case.
+func similarity(q, candidate string) (float64, bool) {
+	qn, cn := utf8.RuneCountInString(q), utf8.RuneCountInString(candidate)
+	edits := MaxEdits(qn)
+	if qn-cn > edits || cn-qn > edits {
+		return 0, false
+	}
+	d := Distance(q, candidate)
+	if d > edits {
+		return 0, false
+	}
+	return 1 - float64(d)/float64(max(qn, cn)), true
+}
+
+// Score reports whether every word of query is within a few typos of some
+// word of text and, if so, how closely they match on average, from 0 to 1.
+// Words are compared case-insensitively. Hostname-like words such as
+// web-01.example.com match whole or part by part.
+func Score(query, text string) (float64, bool) {
+	queryWords := words(query)
+	if len(queryWords) == 0 {
+		return 0, false
+	}
+
+	candidates := make(map[string]bool)
+	for _, w := range words(text) {
+		candidates[w.text] = true
+		for _, part := range w.parts {
+			candidates[part.text] = true
+		}
+	}
+
+	var total float64
+	for _, q := range queryWords {
+		best, found := bestMatch(q.text, candidates)
+		if len(q.parts) > 0 {
+			var sum float64
+			partsFound := true
+			for _, part := range q.parts {
+				sim, ok := bestMatch(part.text, candidates)
+				if !ok {
+					partsFound = false
+					break
+				}
+				sum += sim
+			}
+			if partsFound && sum/float64(len(q.parts)) > best {
+				best, found = sum/float64(len(q.parts)), true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total / float64(len(queryWords)), true
+}
+
+func bestMatch(q string, candidates map[string]bool) (float64, bool) {
+	if candidates[q] {
+		return 1, true
+	}
+	best, found := 0.0, false
+	for c := range candidates {
+		if sim, ok := similarity(q, c); ok && sim > best {
+			best, found = sim, true
+		}
+	}
+	return best, found
+}
+
+// Spans returns the ranges of line holding words, or parts of joined words,
+// within a few typos of a word or part of query, in order.
+func Spans(query, line string) []Span {
+	var queryTexts []string
+	for _, q := range words(query) {
+		queryTexts = append(queryTexts, q.text)
+		for _, part := range q.parts {
+			queryTexts = append(queryTexts, part.text)
+		}
+	}
+
+	var spans []Span
+	for _, w := range words(line) {
+		if matchesAny(w.text, queryTexts) {
+			spans = append(spans, w.Span)
+			continue
+		}
+		for _, part := range w.parts {
+			if matchesAny(part.text, queryTexts) {
+				spans = append(spans, part.Span)
+			}
+		}
+	}
+	return spans
+}
+
+func matchesAny(candidate string, queries []string) bool {
+	for _, q := range queries {
+		if _, ok := similarity(q, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits s into lower-case words of letters and digits, which may be
+// joined by '-', '.' or '_'.
+func words(s string) []word {
+	var result []word
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		text := strings.TrimRight(s[start:end], "-._")
+		if text != "" {
+			w := word{text: strings.ToLower(text), Span: Span{start, start + len(text)}}
+			if strings.ContainsAny(text, "-._") {
+				w.parts = parts(s, w.Span)
+			}
+			result = append(result, w)
+		}
+		start = -1
+	}
+
+	for i, r := range s {
+		switch {
+		case isWordRune(r):
+			if start < 0 {
+				start = i
+			}
+		case r == '-' || r == '.' || r == '_':
+			// Joins words, but cannot start one.
+		default:
+			flush(i)
+		}
+	}
+	flush(len(s))
+	return result
+}
+
+// parts splits the joined word at span of s into its letters and digits.
+func parts(s string, span Span) []word {
+	var result []word
+	start := -1
+	for i, r := range s[span.Start:span.End] + " " {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			part := Span{span.Start + start, span.Start + i}
+			result = append(result, word{text: strings.ToLower(s[part.Start:part.End]), Span: part})
+			start = -1
+		}
+	}
+	return result
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
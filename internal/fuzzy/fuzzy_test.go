@@ -0,0 +1,83 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"deploy", "deploy", 0},
+		{"deploy", "deplay", 1},
+		{"deploy", "deploys", 1},
+		{"deploy", "eploy", 1},
+		{"prod", "prdo", 1},
+		{"prod", "rpod", 1},
+		{"ca", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d; want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	for n, want := range map[int]int{1: 0, 2: 0, 3: 1, 5: 1, 6: 2, 9: 2, 10: 3, 40: 3} {
+		if got := MaxEdits(n); got != want {
+			t.Errorf("MaxEdits(%d) = %d; want %d", n, got, want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		query, text string
+		matched     bool
+	}{
+		{"deploy", "Deploy the API", true},
+		{"depoly", "Deploy the API", true},
+		{"deploy api", "Deploy the API", true},
+		{"deploy database", "Deploy the API", false},
+		{"ap", "Deploy the API", false},
+		{"web-prdo-01", "restart web-prod-01 tonight", true},
+		{"prdo", "restart web-prod-01.example.com", true},
+		{"web-prod-01", "web prod 01", true},
+		{"", "anything", false},
+	}
+	for _, tt := range tests {
+		score, ok := Score(tt.query, tt.text)
+		if ok != tt.matched {
+			t.Errorf("Score(%q, %q) matched = %v; want %v", tt.query, tt.text, ok, tt.matched)
+		}
+		if ok && (score <= 0 || score > 1) {
+			t.Errorf("Score(%q, %q) = %v; want a score in (0, 1]", tt.query, tt.text, score)
+		}
+	}
+
+	exact, _ := Score("deploy", "deploy")
+	typo, _ := Score("deploy", "depoly")
+	if exact != 1 || typo >= exact {
+		t.Errorf("exact match scored %v and a typo %v; want 1 and less", exact, typo)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	line := "Restart web-prod-01 and deplyo"
+	got := Spans("prdo deploy", line)
+	want := []Span{{12, 16}, {24, 30}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Spans = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,433 @@
+// Package index keeps a persistent inverted index of record text for ranked
+// full-text search.
+//
+// The index is stored as a base segment holding every document's term
+// frequencies, plus a log of documents added or removed since the base was
+// written. Put and Delete only append to the log, so keeping the index up to
+// date costs one small record per change; once the log grows long it is
+// folded into a new base.
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// Dir is the namespace the index is kept in within a note or task store.
+const Dir = ".index"
+
+const (
+	metaID = "meta"
+	baseID = "base"
+	logDir = "log"
+	// compactAfter is the number of log entries after which Put and Delete
+	// fold the log into the base segment.
+	compactAfter = 100
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// prefixWeight scales the score of index terms that only start with a
+	// query term, so whole-word matches rank first.
+	prefixWeight = 0.5
+)
+
+// meta describes the index. Generation is bumped whenever a new base is
+// written; log entries and bases from another generation are stale.
+type meta struct {
+	Stem       bool `json:"stem"`
+	Generation int  `json:"generation"`
+}
+
+// base is the stored form of a base segment: every document's term
+// frequencies.
+type base struct {
+	Generation int                       `json:"generation"`
+	Docs       map[string]map[string]int `json:"docs"`
+}
+
+// entry is one change in the log: a document's new term frequencies, or its
+// removal.
+type entry struct {
+	Generation int            `json:"generation"`
+	ID         string         `json:"id"`
+	Terms      map[string]int `json:"terms,omitempty"`
+	Deleted    bool           `json:"deleted,omitempty"`
+}
+
+type Index struct {
+	store  storage.Store
+	meta   meta
+	exists bool
+
+	// docs maps each document to its term frequencies, so a document can be
+	// removed without visiting every term; postings is the inverse.
+	docs     map[string]map[string]int
+	postings map[string]map[string]int
+	lengths  map[string]int
+	total    int
+	// terms is every indexed term in sorted order for prefix lookups, or
+	// nil when it must be rebuilt.
+	terms []string
+}
+
+// Open loads the index from store. A missing, unreadable or incomplete
+// index yields an empty one for which Exists is false.
+func Open(store storage.Store) (*Index, error) {
+	idx := &Index{store: store}
+	idx.reset(false)
+
+	m, ok, err := readMeta(store)
+	if err != nil || !ok {
+		return idx, err
+	}
+
+	raw, err := store.Get(baseID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	var b base
+	if err := json.Unmarshal(raw, &b); err != nil || b.Docs == nil || b.Generation != m.Generation {
+		return idx, nil
+	}
+
+	idx.meta = m
+	for id, terms := range b.Docs {
+		idx.add(id, terms)
+	}
+
+	log, err := readLog(store, m.Generation)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range log {
+		idx.apply(e)
+	}
+	idx.exists = true
+	return idx, nil
+}
+
+func readMeta(store storage.Store) (meta, bool, error) {
+	var m meta
+	raw, err := store.Get(metaID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return m, false, nil
+		}
+		return m, false, fmt.Errorf("failed to read search index: %w", err)
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return m, false, nil
+	}
+	return m, true, nil
+}
+
+// Stemmed reports whether the index kept in store indexes words by their
+// stems, without loading the index.
+func Stemmed(store storage.Store) (bool, error) {
+	m, _, err := readMeta(store)
+	return m.Stem, err
+}
+
+// readLog returns the log entries of generation in the order they were
+// written.
+func readLog(store storage.Store, generation int) ([]entry, error) {
+	log := store.Sub(logDir)
+	ids, err := log.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index log: %w", err)
+	}
+
+	var entries []entry
+	for _, id := range ids {
+		raw, err := log.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read search index log: %w", err)
+		}
+		var e entry
+		if err := json.Unmarshal(raw, &e); err != nil || e.Generation != generation {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (idx *Index) reset(stem bool) {
+	idx.meta.Stem = stem
+	idx.docs = make(map[string]map[string]int)
+	idx.postings = make(map[string]map[string]int)
+	idx.lengths = make(map[string]int)
+	idx.total = 0
+	idx.terms = nil
+}
+
+// Exists reports whether the index has been built. Until then it knows
+// nothing about existing documents and must be rebuilt before searching.
+func (idx *Index) Exists() bool {
+	return idx.exists
+}
+
+func (idx *Index) Stemming() bool {
+	return idx.meta.Stem
+}
+
+// Rebuild clears the index and indexes docs, a map of document ID to text.
+// Save writes the result.
+func (idx *Index) Rebuild(docs map[string]string, stem bool) {
+	idx.reset(stem)
+	for id, text := range docs {
+		idx.add(id, frequencies(text, stem))
+	}
+	idx.exists = true
+}
+
+// Save writes the index as a new base segment and discards the log. The
+// caller must hold the store's lock.
+func (idx *Index) Save() error {
+	m := meta{Stem: idx.meta.Stem, Generation: idx.meta.Generation + 1}
+	raw, err := json.Marshal(base{Generation: m.Generation, Docs: idx.docs})
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := idx.store.Put(baseID, raw); err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+
+	// The new meta makes the base current and every existing log entry
+	// stale, so a crash before the log is cleared loses nothing.
+	if raw, err = json.Marshal(m); err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := idx.store.Put(metaID, raw); err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	if err := idx.store.Sub(logDir).Clear(); err != nil {
+		return fmt.Errorf("failed to clear search index log: %w", err)
+	}
+
+	idx.meta = m
+	idx.exists = true
+	return nil
+}
+
+// Put indexes text under id in the index kept in store, replacing anything
+// indexed for it before. Until the index is built there is nothing to
+// update.
+func Put(store storage.Store, id, text string) error {
+	return appendLog(store, func(m meta) entry {
+		return entry{ID: id, Terms: frequencies(text, m.Stem)}
+	})
+}
+
+// Delete removes id from the index kept in store.
+func Delete(store storage.Store, id string) error {
+	return appendLog(store, func(meta) entry {
+		return entry{ID: id, Deleted: true}
+	})
+}
+
+// appendLog records a change under the store lock, folding the log into a
+// new base once it holds compactAfter entries.
+func appendLog(store storage.Store, change func(meta) entry) error {
+	return store.Update(func(store storage.Store) error {
+		m, ok, err := readMeta(store)
+		if err != nil || !ok {
+			return err
+		}
+
+		e := change(m)
+		e.Generation = m.Generation
+		raw, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode search index: %w", err)
+		}
+
+		log := store.Sub(logDir)
+		seq, err := log.NextID()
+		if err != nil {
+			return fmt.Errorf("failed to update search index: %w", err)
+		}
+		if err := log.Put(seq, raw); err != nil {
+			return fmt.Errorf("failed to update search index: %w", err)
+		}
+
+		ids, err := log.List()
+		if err != nil || len(ids) < compactAfter {
+			return err
+		}
+		idx, err := Open(store)
+		if err != nil || !idx.Exists() {
+			return err
+		}
+		return idx.Save()
+	})
+}
+
+func (idx *Index) apply(e entry) {
+	idx.remove(e.ID)
+	if !e.Deleted {
+		idx.add(e.ID, e.Terms)
+	}
+}
+
+// add indexes a document's term frequencies, replacing anything indexed for
+// it before.
+func (idx *Index) add(id string, terms map[string]int) {
+	idx.remove(id)
+
+	length := 0
+	for term, tf := range terms {
+		postings, ok := idx.postings[term]
+		if !ok {
+			postings = make(map[string]int)
+			idx.postings[term] = postings
+			idx.terms = nil
+		}
+		postings[id] = tf
+		length += tf
+	}
+	idx.docs[id] = terms
+	idx.lengths[id] = length
+	idx.total += length
+}
+
+func (idx *Index) remove(id string) {
+	terms, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for term := range terms {
+		postings := idx.postings[term]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(idx.postings, term)
+			idx.terms = nil
+		}
+	}
+	idx.total -= idx.lengths[id]
+	delete(idx.docs, id)
+	delete(idx.lengths, id)
+}
+
+func frequencies(text string, stem bool) map[string]int {
+	terms := make(map[string]int)
+	for _, term := range Tokenize(text, stem) {
+		terms[term]++
+	}
+	return terms
+}
+
+// Candidates returns the IDs of documents containing every word of text,
+// where an index term matches a word it starts with.
+func (idx *Index) Candidates(text string) map[string]bool {
+	var result map[string]bool
+	for _, word := range Tokenize(text, idx.meta.Stem) {
+		found := make(map[string]bool)
+		for _, term := range idx.expand(word) {
+			for id := range idx.postings[term] {
+				if result == nil || result[id] {
+					found[id] = true
+				}
+			}
+		}
+		result = found
+	}
+	return result
+}
+
+// Score returns the BM25 score of every document matching a word of text.
+func (idx *Index) Score(text string) map[string]float64 {
+	scores := make(map[string]float64)
+	n := float64(len(idx.lengths))
+	if n == 0 {
+		return scores
+	}
+	avgLength := float64(idx.total) / n
+
+	for _, word := range Tokenize(text, idx.meta.Stem) {
+		for _, term := range idx.expand(word) {
+			postings := idx.postings[term]
+			df := float64(len(postings))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			weight := 1.0
+			if term != word {
+				weight = prefixWeight
+			}
+			for id, tf := range postings {
+				f := float64(tf)
+				norm := 1 - bm25B + bm25B*float64(idx.lengths[id])/avgLength
+				scores[id] += weight * idf * f * (bm25K1 + 1) / (f + bm25K1*norm)
+			}
+		}
+	}
+	return scores
+}
+
+// expand returns the index terms that start with word.
+func (idx *Index) expand(word string) []string {
+	if idx.terms == nil {
+		idx.terms = make([]string, 0, len(idx.postings))
+		for term := range idx.postings {
+			idx.terms = append(idx.terms, term)
+		}
+		sort.Strings(idx.terms)
+	}
+
+	var terms []string
+	for i := sort.SearchStrings(idx.terms, word); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], word); i++ {
+		terms = append(terms, idx.terms[i])
+	}
+	return terms
+}
+
+// WordMatcher returns a function reporting whether a word of a document is
+// found by a word of text, as Candidates and Score look words up: the
+// word's term starts with the term of a word of text. stem must match the
+// index's setting.
+func WordMatcher(text string, stem bool) func(word string) bool {
+	queryTerms := Tokenize(text, stem)
+	return func(word string) bool {
+		for _, term := range Tokenize(word, stem) {
+			for _, q := range queryTerms {
+				if strings.HasPrefix(term, q) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// Matcher answers whether documents contain query words, looking each word
+// up in the index only once.
+type Matcher struct {
+	idx  *Index
+	hits map[string]map[string]bool
+}
+
+func (idx *Index) Matcher() *Matcher {
+	return &Matcher{idx: idx, hits: make(map[string]map[string]bool)}
+}
+
+// Contains reports whether document id contains every word of text, as
+// Candidates does. ok is false when text holds no indexable words.
+func (m *Matcher) Contains(id, text string) (found, ok bool) {
+	if len(Tokenize(text, false)) == 0 {
+		return false, false
+	}
+	hits, cached := m.hits[text]
+	if !cached {
+		hits = m.idx.Candidates(text)
+		m.hits[text] = hits
+	}
+	return hits[id], true
+}
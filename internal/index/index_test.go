@@ -0,0 +1,220 @@
+package index
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+var testDocs = map[string]string{
+	"1": "deploy the api to staging",
+	"2": "deploy deploy deploy the database",
+	"3": "review the deployment checklist",
+	"4": "water the plants",
+}
+
+func sortedIDs(set map[string]bool) []string {
+	var ids []string
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func newTestIndex(t *testing.T, stem bool) *Index {
+	t.Helper()
+	idx, err := Open(storage.NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx.Rebuild(testDocs, stem)
+	return idx
+}
+
+func TestCandidates(t *testing.T) {
+	idx := newTestIndex(t, false)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"deploy", []string{"1", "2", "3"}},
+		{"DEPLOY api", []string{"1"}},
+		{"dep", []string{"1", "2", "3"}},
+		{"the", []string{"1", "2", "3", "4"}},
+		{"deployments", nil},
+		{"garden", nil},
+	}
+	for _, tt := range tests {
+		if got := sortedIDs(idx.Candidates(tt.text)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Candidates(%q) = %v; want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	idx := newTestIndex(t, false)
+	scores := idx.Score("deploy")
+
+	if _, ok := scores["4"]; ok {
+		t.Errorf("document without the term was scored: %v", scores)
+	}
+	// More occurrences rank higher, and a whole-word match beats a prefix
+	// match ("deployment").
+	if !(scores["2"] > scores["1"] && scores["1"] > scores["3"] && scores["3"] > 0) {
+		t.Errorf("scores = %v; want 2 > 1 > 3 > 0", scores)
+	}
+
+	// A rarer term carries more weight than a common one.
+	rare := idx.Score("api")["1"]
+	common := idx.Score("the")["1"]
+	if rare <= common {
+		t.Errorf("score of rare term = %v, common term = %v; want rare > common", rare, common)
+	}
+
+	if got := idx.Score("garden"); len(got) != 0 {
+		t.Errorf("Score(garden) = %v; want none", got)
+	}
+}
+
+func TestStemming(t *testing.T) {
+	idx := newTestIndex(t, true)
+	if !idx.Stemming() {
+		t.Fatal("Stemming() = false after Rebuild with stem")
+	}
+	if got := sortedIDs(idx.Candidates("plant")); !reflect.DeepEqual(got, []string{"4"}) {
+		t.Errorf("Candidates(plant) = %v; want [4]", got)
+	}
+	if got := sortedIDs(idx.Candidates("deployed")); !reflect.DeepEqual(got, []string{"1", "2", "3"}) {
+		t.Errorf("Candidates(deployed) = %v; want [1 2 3]", got)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	store := storage.NewMemoryStore()
+
+	// Updates before the index is built are ignored.
+	if err := Put(store, "9", "ignored"); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := Open(store)
+	if err != nil || idx.Exists() {
+		t.Fatalf("Open before build: exists = %v, err = %v", idx.Exists(), err)
+	}
+
+	idx.Rebuild(testDocs, false)
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Put(store, "1", "water the garden"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Put(store, "5", "deploy the docs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(store, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err = Open(store)
+	if err != nil || !idx.Exists() {
+		t.Fatalf("Open after build: exists = %v, err = %v", idx.Exists(), err)
+	}
+	if got := sortedIDs(idx.Candidates("deploy")); !reflect.DeepEqual(got, []string{"3", "5"}) {
+		t.Errorf("Candidates(deploy) = %v; want [3 5]", got)
+	}
+	if got := sortedIDs(idx.Candidates("water")); !reflect.DeepEqual(got, []string{"1", "4"}) {
+		t.Errorf("Candidates(water) = %v; want [1 4]", got)
+	}
+	if got := sortedIDs(idx.Candidates("ignored")); len(got) != 0 {
+		t.Errorf("Candidates(ignored) = %v; want none", got)
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	store := storage.NewMemoryStore()
+	idx, err := Open(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx.Rebuild(nil, false)
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= compactAfter+10; i++ {
+		if err := Put(store, strconv.Itoa(i), "note number "+strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The log was folded into the base once and holds only what came after.
+	if ids, _ := store.Sub(logDir).List(); len(ids) != 10 {
+		t.Errorf("log holds %d entries; want 10", len(ids))
+	}
+	idx, err = Open(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(idx.Candidates("note")); got != compactAfter+10 {
+		t.Errorf("Candidates(note) found %d documents; want %d", got, compactAfter+10)
+	}
+	if got := sortedIDs(idx.Candidates("105")); !reflect.DeepEqual(got, []string{"105"}) {
+		t.Errorf("Candidates(105) = %v; want [105]", got)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := newTestIndex(t, false).Matcher()
+	tests := []struct {
+		id, text  string
+		found, ok bool
+	}{
+		{"1", "api", true, true},
+		{"2", "api", false, true},
+		{"3", "deploy checklist", true, true},
+		{"1", "--", false, false},
+	}
+	for _, tt := range tests {
+		found, ok := m.Contains(tt.id, tt.text)
+		if found != tt.found || ok != tt.ok {
+			t.Errorf("Contains(%q, %q) = %v, %v; want %v, %v", tt.id, tt.text, found, ok, tt.found, tt.ok)
+		}
+	}
+}
+
+func TestWordMatcher(t *testing.T) {
+	tests := []struct {
+		text, word string
+		stem, want bool
+	}{
+		{"deploy", "Deploy", false, true},
+		{"deploy", "deployment", false, true},
+		{"deploy", "redeploy", false, false},
+		{"deployed", "deploying", false, false},
+		{"deployed", "deploying", true, true},
+		{"plants", "plant", true, true},
+		{"water deploy", "watering", false, true},
+	}
+	for _, tt := range tests {
+		if got := WordMatcher(tt.text, tt.stem)(tt.word); got != tt.want {
+			t.Errorf("WordMatcher(%q, stem %v)(%q) = %v; want %v", tt.text, tt.stem, tt.word, got, tt.want)
+		}
+	}
+
+	// Every word it accepts is one the index finds for the same text.
+	idx := newTestIndex(t, true)
+	match := WordMatcher("deployed", true)
+	for id, doc := range testDocs {
+		found := false
+		for _, word := range Tokenize(doc, false) {
+			found = found || match(word)
+		}
+		if found != idx.Candidates("deployed")[id] {
+			t.Errorf("document %s: WordMatcher found a word = %v; index candidate = %v", id, found, !found)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package index
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lowercase words of letters and digits. With stem
+// set, common English suffixes are removed so that "deploying" and "deployed"
+// both index as "deploy".
+func Tokenize(text string, stem bool) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if stem {
+		for i, word := range words {
+			words[i] = Stem(word)
+		}
+	}
+	return words
+}
+
+// Stem strips plural and verb suffixes from an English word. It is a light
+// stemmer: it only has to map related forms to the same term, not produce
+// dictionary words.
+func Stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+	case strings.HasSuffix(word, "s"):
+		word = word[:len(word)-1]
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed", "ly"} {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if ok && len(stem) >= 3 && hasVowel(stem) {
+			word = stem
+			if n := len(word); n > 2 && word[n-1] == word[n-2] && !strings.ContainsRune("lsz", rune(word[n-1])) {
+				word = word[:n-1]
+			}
+			break
+		}
+	}
+
+	return word
+}
+
+func hasVowel(s string) bool {
+	return strings.ContainsAny(s, "aeiouy")
+}
@@ -1,21 +1,30 @@
 package notes
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/wltechblog/notes/internal/index"
 	"github.com/wltechblog/notes/internal/platform"
+	"github.com/wltechblog/notes/internal/query"
+	"github.com/wltechblog/notes/internal/record"
+	"github.com/wltechblog/notes/internal/storage"
+	"github.com/wltechblog/notes/internal/tags"
+	"github.com/wltechblog/notes/internal/views"
 )
 
 type Note struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
+	Tags      []string  `json:"tags,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Content   string    `json:"content"`
@@ -25,8 +34,32 @@ func (n *Note) Path(baseDir string) string {
 	return filepath.Join(baseDir, n.ID+".txt")
 }
 
+type Filter struct {
+	Tags    []string
+	NotTags []string
+	Query   query.Node
+
+	// matcher, when set, answers bare words in Query from the search index.
+	matcher *index.Matcher
+}
+
+func (f Filter) Match(note *Note) bool {
+	var r query.Record = noteRecord{note}
+	if f.matcher != nil {
+		r = indexedRecord{noteRecord{note}, f.matcher}
+	}
+	return tags.Match(note.Tags, f.Tags, f.NotTags) && query.Match(f.Query, r)
+}
+
+type Revision struct {
+	Number int  `json:"number"`
+	Note   Note `json:"note"`
+}
+
+const historyDir = ".history"
+
 type NoteManager struct {
-	baseDir string
+	store storage.Store
 }
 
 func NewNoteManager() (*NoteManager, error) {
@@ -35,61 +68,75 @@ func NewNoteManager() (*NoteManager, error) {
 		return nil, err
 	}
 
-	if err := os.MkdirAll(baseDir, platform.GetDataDirPerm()); err != nil {
+	store, err := storage.NewFileStore(baseDir)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create notes directory: %w", err)
 	}
 
-	return &NoteManager{baseDir: baseDir}, nil
+	return NewNoteManagerWithStore(store), nil
+}
+
+func NewNoteManagerWithStore(store storage.Store) *NoteManager {
+	return &NoteManager{store: store}
 }
 
-func (nm *NoteManager) ListNotes() ([]Note, error) {
+// Views returns the saved note views.
+func (nm *NoteManager) Views() *views.Views {
+	return views.New(nm.store.Sub(views.Dir))
+}
+
+func (nm *NoteManager) ListNotes(filter Filter) ([]Note, error) {
 	var notes []Note
 
-	entries, err := os.ReadDir(nm.baseDir)
+	ids, err := nm.store.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read notes directory: %w", err)
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".txt") {
-			continue
-		}
-
-		id := strings.TrimSuffix(entry.Name(), ".txt")
+	for _, id := range ids {
 		note, err := nm.loadNote(id)
 		if err != nil {
 			continue
 		}
-		notes = append(notes, note)
+		if filter.Match(&note) {
+			notes = append(notes, note)
+		}
 	}
 
 	return notes, nil
 }
 
 func (nm *NoteManager) CreateNote(name string, content string) (*Note, error) {
-	if name == "" {
-		name = strconv.FormatInt(time.Now().Unix(), 10)
+	note := &Note{
+		Name:    name,
+		Content: content,
 	}
 
-	timestamp := time.Now()
-	id, err := nm.getNextID()
-	if err != nil {
+	if err := nm.Create(note); err != nil {
 		return nil, err
 	}
 
-	note := &Note{
-		ID:        id,
-		Name:      name,
-		CreatedAt: timestamp,
-		UpdatedAt: timestamp,
-		Content:   content,
+	return note, nil
+}
+
+// Create assigns note a new ID and creation timestamps and saves it. An
+// empty name defaults to the current Unix time.
+func (nm *NoteManager) Create(note *Note) error {
+	if note.Name == "" {
+		note.Name = strconv.FormatInt(time.Now().Unix(), 10)
 	}
 
-	if err := nm.saveNote(note); err != nil {
-		return nil, err
+	timestamp := time.Now()
+	id, err := nm.store.NextID()
+	if err != nil {
+		return err
 	}
 
-	return note, nil
+	note.ID = id
+	note.CreatedAt = timestamp
+	note.UpdatedAt = timestamp
+
+	return nm.saveNote(note)
 }
 
 func (nm *NoteManager) GetNote(id string) (*Note, error) {
@@ -101,93 +148,161 @@ func (nm *NoteManager) GetNote(id string) (*Note, error) {
 }
 
 func (nm *NoteManager) UpdateNote(id string, content string) (*Note, error) {
-	note, err := nm.loadNote(id)
-	if err != nil {
-		return nil, err
-	}
+	return nm.modify(id, func(note *Note) error {
+		note.Content = content
+		note.UpdatedAt = time.Now()
+		return nil
+	})
+}
 
-	note.Content = content
-	note.UpdatedAt = time.Now()
+func (nm *NoteManager) DeleteNote(id string) error {
+	return nm.update(func(tx *NoteManager) error {
+		data, err := tx.store.Get(id)
+		if err != nil {
+			return fmt.Errorf("failed to delete note: %w", err)
+		}
 
-	if err := nm.saveNote(&note); err != nil {
-		return nil, err
-	}
+		if err := tx.trashRecord(id, data); err != nil {
+			return err
+		}
 
-	return &note, nil
+		if err := tx.store.Delete(id); err != nil {
+			return fmt.Errorf("failed to delete note: %w", err)
+		}
+		return tx.unindexNote(id)
+	})
 }
 
-func (nm *NoteManager) DeleteNote(id string) error {
-	notePath := filepath.Join(nm.baseDir, id+".txt")
-	if err := os.Remove(notePath); err != nil {
+func (nm *NoteManager) PurgeNote(id string) error {
+	if err := nm.store.Delete(id); err != nil {
 		return fmt.Errorf("failed to delete note: %w", err)
 	}
-	return nil
+	if err := nm.history(id).Clear(); err != nil {
+		return fmt.Errorf("failed to delete note history: %w", err)
+	}
+	return nm.unindexNote(id)
 }
 
-func (nm *NoteManager) SearchNotes(keyword string) ([]Note, error) {
-	notes, err := nm.ListNotes()
+func (nm *NoteManager) AddTags(id string, tagList ...string) (*Note, error) {
+	return nm.modify(id, func(note *Note) error {
+		for _, tag := range tagList {
+			note.Tags = tags.Add(note.Tags, tag)
+		}
+		note.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (nm *NoteManager) RemoveTags(id string, tagList ...string) (*Note, error) {
+	return nm.modify(id, func(note *Note) error {
+		for _, tag := range tagList {
+			note.Tags = tags.Remove(note.Tags, tag)
+		}
+		note.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (nm *NoteManager) TagCounts() (map[string]int, error) {
+	noteList, err := nm.ListNotes(Filter{})
 	if err != nil {
 		return nil, err
 	}
 
-	var matchingNotes []Note
-	for _, note := range notes {
-		if strings.Contains(strings.ToLower(note.Content), strings.ToLower(keyword)) ||
-			strings.Contains(strings.ToLower(note.Name), strings.ToLower(keyword)) {
-			matchingNotes = append(matchingNotes, note)
+	counts := make(map[string]int)
+	for _, note := range noteList {
+		for _, tag := range note.Tags {
+			counts[tag]++
 		}
 	}
-
-	return matchingNotes, nil
+	return counts, nil
 }
 
 func (nm *NoteManager) loadNote(id string) (Note, error) {
-	notePath := filepath.Join(nm.baseDir, id+".txt")
-	data, err := os.ReadFile(notePath)
+	data, err := nm.store.Get(id)
 	if err != nil {
 		return Note{}, fmt.Errorf("failed to read note: %w", err)
 	}
 
-	lines := strings.Split(string(data), "\n")
-	if len(lines) < 3 {
-		return Note{}, fmt.Errorf("invalid note format")
+	return parseNote(id, data)
+}
+
+func parseNote(id string, data []byte) (Note, error) {
+	fields, content, err := record.Parse(data)
+	if err != nil {
+		return Note{}, fmt.Errorf("invalid note format: %w", err)
 	}
 
-	createdAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[0], "Created: "))
+	createdAt, err := time.Parse(time.RFC3339, record.Get(fields, "Created"))
 	if err != nil {
 		return Note{}, fmt.Errorf("failed to parse created timestamp: %w", err)
 	}
 
-	updatedAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[1], "Updated: "))
+	updatedAt, err := time.Parse(time.RFC3339, record.Get(fields, "Updated"))
 	if err != nil {
 		return Note{}, fmt.Errorf("failed to parse updated timestamp: %w", err)
 	}
 
-	name := strings.TrimPrefix(lines[2], "Name: ")
-	content := strings.Join(lines[3:], "\n")
-
 	return Note{
 		ID:        id,
-		Name:      name,
+		Name:      record.Get(fields, "Name"),
+		Tags:      tags.Parse(record.Get(fields, "Tags")),
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
 		Content:   content,
 	}, nil
 }
 
-func (nm *NoteManager) saveNote(note *Note) error {
+func formatNote(note *Note) []byte {
 	var content string
 	content += fmt.Sprintf("Created: %s\n", note.CreatedAt.Format(time.RFC3339))
 	content += fmt.Sprintf("Updated: %s\n", note.UpdatedAt.Format(time.RFC3339))
+	if len(note.Tags) > 0 {
+		content += fmt.Sprintf("Tags: %s\n", tags.Format(note.Tags))
+	}
 	content += fmt.Sprintf("Name: %s\n", note.Name)
 	content += note.Content
+	return []byte(content)
+}
 
-	notePath := filepath.Join(nm.baseDir, note.ID+".txt")
-	if err := os.WriteFile(notePath, []byte(content), platform.GetDataFilePerm()); err != nil {
+func (nm *NoteManager) saveNote(note *Note) error {
+	if err := nm.archiveRevision(note); err != nil {
+		return err
+	}
+
+	if err := nm.store.Put(note.ID, formatNote(note)); err != nil {
 		return fmt.Errorf("failed to save note: %w", err)
 	}
 
-	return nil
+	return nm.indexNote(note)
+}
+
+// update runs fn with a manager whose store already holds the write lock,
+// so a load-modify-save through it cannot interleave with another writer.
+func (nm *NoteManager) update(fn func(tx *NoteManager) error) error {
+	return nm.store.Update(func(store storage.Store) error {
+		return fn(&NoteManager{store: store})
+	})
+}
+
+// modify loads a note, applies change and saves the result under the store
+// lock.
+func (nm *NoteManager) modify(id string, change func(note *Note) error) (*Note, error) {
+	var note Note
+	err := nm.update(func(tx *NoteManager) error {
+		var err error
+		if note, err = tx.loadNote(id); err != nil {
+			return err
+		}
+		if err := change(&note); err != nil {
+			return err
+		}
+		return tx.saveNote(&note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
 }
 
 func (nm *NoteManager) EditInEditor(note *Note) error {
@@ -231,30 +346,103 @@ func (nm *NoteManager) EditInEditor(note *Note) error {
 	note.Content = string(editedContent)
 	note.UpdatedAt = time.Now()
 
-	return nm.saveNote(note)
+	_, err = nm.UpdateNote(note.ID, note.Content)
+	return err
 }
 
-func (nm *NoteManager) getNextID() (string, error) {
-	counterFile := filepath.Join(nm.baseDir, ".counter")
+func (nm *NoteManager) history(id string) storage.Store {
+	return nm.store.Sub(historyDir).Sub(id)
+}
 
-	data, err := os.ReadFile(counterFile)
+// archiveRevision keeps the stored version of note in its history before it
+// is overwritten. Saves that change nothing but the timestamp, and the empty
+// placeholder written by CreateNote before the first edit, are not kept.
+func (nm *NoteManager) archiveRevision(note *Note) error {
+	prevData, err := nm.store.Get(note.ID)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return "", fmt.Errorf("failed to read counter file: %w", err)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
 		}
-		data = []byte("0")
+		return fmt.Errorf("failed to read note: %w", err)
 	}
 
-	currentID, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if prev, err := parseNote(note.ID, prevData); err == nil {
+		if prev.Content == "" && prev.CreatedAt.Equal(prev.UpdatedAt) {
+			return nil
+		}
+		unchanged := *note
+		unchanged.UpdatedAt = prev.UpdatedAt
+		if bytes.Equal(formatNote(&unchanged), prevData) {
+			return nil
+		}
+	}
+
+	history := nm.history(note.ID)
+	rev, err := history.NextID()
 	if err != nil {
-		return "", fmt.Errorf("failed to parse counter: %w", err)
+		return fmt.Errorf("failed to allocate revision: %w", err)
+	}
+	if err := history.Put(rev, prevData); err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
 	}
 
-	nextID := currentID + 1
+	return nil
+}
+
+func (nm *NoteManager) History(id string) ([]Revision, error) {
+	if _, err := nm.loadNote(id); err != nil {
+		return nil, err
+	}
+
+	revs, err := nm.history(id).List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note history: %w", err)
+	}
+
+	var revisions []Revision
+	for _, rev := range revs {
+		number, err := strconv.Atoi(rev)
+		if err != nil {
+			continue
+		}
+		note, err := nm.GetRevision(id, number)
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, Revision{Number: number, Note: *note})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+
+	return revisions, nil
+}
+
+func (nm *NoteManager) GetRevision(id string, rev int) (*Note, error) {
+	data, err := nm.history(id).Get(strconv.Itoa(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision %d of note %s: %w", rev, id, err)
+	}
 
-	if err := os.WriteFile(counterFile, []byte(strconv.FormatInt(nextID, 10)), platform.GetDataFilePerm()); err != nil {
-		return "", fmt.Errorf("failed to write counter: %w", err)
+	note, err := parseNote(id, data)
+	if err != nil {
+		return nil, err
 	}
+	return &note, nil
+}
+
+func (nm *NoteManager) RestoreRevision(id string, rev int) (*Note, error) {
+	return nm.modify(id, func(note *Note) error {
+		old, err := nm.GetRevision(id, rev)
+		if err != nil {
+			return err
+		}
 
-	return strconv.FormatInt(nextID, 10), nil
+		note.Name = old.Name
+		note.Tags = old.Tags
+		note.Content = old.Content
+		note.UpdatedAt = time.Now()
+		return nil
+	})
 }
@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"time"
+
+	"github.com/wltechblog/notes/internal/index"
+	"github.com/wltechblog/notes/internal/query"
+)
+
+// QueryFields are the fields note queries can filter on.
+var QueryFields = query.Schema{
+	"id":      query.Keyword,
+	"name":    query.Text,
+	"content": query.Text,
+	"tag":     query.List,
+	"tags":    query.List,
+	"created": query.Time,
+	"updated": query.Time,
+}
+
+func ParseQuery(s string) (query.Node, error) {
+	return query.Parse(s, QueryFields, time.Now())
+}
+
+type noteRecord struct {
+	note *Note
+}
+
+func (r noteRecord) Text() string {
+	return r.note.Name + "\n" + r.note.Content
+}
+
+func (r noteRecord) Field(name string) query.Value {
+	note := r.note
+	switch name {
+	case "id":
+		return query.Value{Str: note.ID}
+	case "name":
+		return query.Value{Str: note.Name}
+	case "content":
+		return query.Value{Str: note.Content}
+	case "tag", "tags":
+		return query.Value{List: note.Tags}
+	case "created":
+		return query.Value{Time: &note.CreatedAt}
+	case "updated":
+		return query.Value{Time: &note.UpdatedAt}
+	}
+	return query.Value{}
+}
+
+// indexedRecord is a noteRecord whose bare words are looked up in the
+// search index, so they match at the start of indexed words.
+type indexedRecord struct {
+	noteRecord
+	matcher *index.Matcher
+}
+
+func (r indexedRecord) MatchTerm(word string) (bool, bool) {
+	return r.matcher.Contains(r.note.ID, word)
+}
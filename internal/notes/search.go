@@ -0,0 +1,209 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wltechblog/notes/internal/fuzzy"
+	"github.com/wltechblog/notes/internal/index"
+	"github.com/wltechblog/notes/internal/query"
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// Result is a note matched by SearchNotes with its relevance score.
+type Result struct {
+	Note
+	Score float64 `json:"score"`
+}
+
+// SearchNotes returns the notes matching filter and the query q, best match
+// first. Plain words are looked up in the search index, which is built on
+// first use, and match the start of words in the name or content; quoted
+// phrases and field predicates are checked against each note (see package
+// query). Results are ranked with BM25.
+func (nm *NoteManager) SearchNotes(q string, filter Filter) ([]Result, error) {
+	node, err := ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Query != nil {
+		node = query.And(filter.Query, node)
+	}
+	filter.Query = node
+
+	idx, err := nm.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+	filter.matcher = idx.Matcher()
+
+	// Notes lacking a required word cannot match, so only the index's
+	// candidates are read when the query has one.
+	var ids []string
+	if required := query.Required(node); len(required) > 0 {
+		for id := range idx.Candidates(strings.Join(required, " ")) {
+			ids = append(ids, id)
+		}
+	} else if ids, err = nm.store.List(); err != nil {
+		return nil, fmt.Errorf("failed to read notes directory: %w", err)
+	}
+
+	scores := idx.Score(strings.Join(query.Terms(node), " "))
+	var results []Result
+	for _, id := range ids {
+		note, err := nm.loadNote(id)
+		if err != nil {
+			continue
+		}
+		if filter.Match(&note) {
+			results = append(results, Result{Note: note, Score: scores[id]})
+		}
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// SearchWords returns a function reporting whether a word of a note is
+// one that a plain word of the query q finds in the search index, to
+// highlight what SearchNotes matched.
+func (nm *NoteManager) SearchWords(q string) (func(word string) bool, error) {
+	node, err := ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	stem, err := index.Stemmed(nm.store.Sub(index.Dir))
+	if err != nil {
+		return nil, err
+	}
+	return index.WordMatcher(strings.Join(query.Terms(node), " "), stem), nil
+}
+
+// Scope selects the parts of a note SearchNotesRegex matches.
+type Scope string
+
+const (
+	ScopeName    Scope = "name"
+	ScopeContent Scope = "content"
+	ScopeBoth    Scope = "both"
+)
+
+// SearchNotesRegex returns the notes matching filter whose name or content,
+// as scope selects, match re. Notes with the most matches come first.
+func (nm *NoteManager) SearchNotesRegex(re *regexp.Regexp, scope Scope, filter Filter) ([]Result, error) {
+	return nm.rank(filter, func(note *Note) float64 {
+		var count int
+		if scope != ScopeContent {
+			count += len(re.FindAllStringIndex(note.Name, -1))
+		}
+		if scope != ScopeName {
+			count += len(re.FindAllStringIndex(note.Content, -1))
+		}
+		return float64(count)
+	})
+}
+
+// SearchNotesFuzzy returns the notes matching filter that contain every
+// word of q, allowing for a few typos in each (see package fuzzy). Closer
+// matches come first, scored from 0 to 1.
+func (nm *NoteManager) SearchNotesFuzzy(q string, filter Filter) ([]Result, error) {
+	return nm.rank(filter, func(note *Note) float64 {
+		score, _ := fuzzy.Score(q, noteRecord{note}.Text())
+		return score
+	})
+}
+
+// rank scores the notes matching filter, leaving out those scoring 0, and
+// orders them best first.
+func (nm *NoteManager) rank(filter Filter, score func(*Note) float64) ([]Result, error) {
+	noteList, err := nm.ListNotes(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, note := range noteList {
+		if s := score(&note); s > 0 {
+			results = append(results, Result{Note: note, Score: s})
+		}
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// sortResults orders results by descending score, then by ID.
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return storage.LessID(results[i].ID, results[j].ID)
+	})
+}
+
+// Reindex rebuilds the search index from every note and returns how many
+// were indexed. With stem set, words are indexed by their stems.
+func (nm *NoteManager) Reindex(stem bool) (int, error) {
+	var count int
+	err := nm.store.Sub(index.Dir).Update(func(store storage.Store) error {
+		idx, err := index.Open(store)
+		if err != nil {
+			return err
+		}
+		count, err = nm.rebuildIndex(idx, stem)
+		return err
+	})
+	return count, err
+}
+
+func (nm *NoteManager) rebuildIndex(idx *index.Index, stem bool) (int, error) {
+	noteList, err := nm.ListNotes(Filter{})
+	if err != nil {
+		return 0, err
+	}
+
+	docs := make(map[string]string, len(noteList))
+	for _, note := range noteList {
+		docs[note.ID] = noteRecord{&note}.Text()
+	}
+	idx.Rebuild(docs, stem)
+
+	if err := idx.Save(); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// searchIndex opens the search index, building it under the index lock if
+// it does not exist yet.
+func (nm *NoteManager) searchIndex() (*index.Index, error) {
+	store := nm.store.Sub(index.Dir)
+	idx, err := index.Open(store)
+	if err != nil || idx.Exists() {
+		return idx, err
+	}
+
+	err = store.Update(func(store storage.Store) error {
+		var err error
+		if idx, err = index.Open(store); err != nil || idx.Exists() {
+			return err
+		}
+		_, err = nm.rebuildIndex(idx, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// indexNote brings note's entry in the search index up to date. Until the
+// first search builds the index there is nothing to update.
+func (nm *NoteManager) indexNote(note *Note) error {
+	return index.Put(nm.store.Sub(index.Dir), note.ID, noteRecord{note}.Text())
+}
+
+func (nm *NoteManager) unindexNote(id string) error {
+	return index.Delete(nm.store.Sub(index.Dir), id)
+}
@@ -0,0 +1,35 @@
+package notes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// SortKeys are the orders SortNotes accepts.
+var SortKeys = []string{"id", "name", "created", "updated"}
+
+// SortNotes orders notes by key: IDs numerically, names alphabetically,
+// oldest created first, or most recently updated first.
+func SortNotes(notes []Note, key string) error {
+	var less func(a, b *Note) bool
+	switch key {
+	case "", "id":
+		less = func(a, b *Note) bool { return storage.LessID(a.ID, b.ID) }
+	case "name":
+		less = func(a, b *Note) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "created":
+		less = func(a, b *Note) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "updated":
+		less = func(a, b *Note) bool { return a.UpdatedAt.After(b.UpdatedAt) }
+	default:
+		return fmt.Errorf("invalid sort key: %s (must be one of: %s)", key, strings.Join(SortKeys, ", "))
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		return less(&notes[i], &notes[j])
+	})
+	return nil
+}
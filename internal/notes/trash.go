@@ -0,0 +1,128 @@
+package notes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+const trashDir = ".trash"
+
+type TrashedNote struct {
+	Note
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (nm *NoteManager) trash() storage.Store {
+	return nm.store.Sub(trashDir)
+}
+
+func (nm *NoteManager) trashRecord(id string, data []byte) error {
+	entry := fmt.Sprintf("Deleted: %s\n", time.Now().Format(time.RFC3339)) + string(data)
+	if err := nm.trash().Put(id, []byte(entry)); err != nil {
+		return fmt.Errorf("failed to move note to trash: %w", err)
+	}
+	return nil
+}
+
+func (nm *NoteManager) loadTrashed(id string) (TrashedNote, []byte, error) {
+	data, err := nm.trash().Get(id)
+	if err != nil {
+		return TrashedNote{}, nil, fmt.Errorf("failed to read trashed note: %w", err)
+	}
+
+	header, record, found := strings.Cut(string(data), "\n")
+	if !found || !strings.HasPrefix(header, "Deleted: ") {
+		return TrashedNote{}, nil, fmt.Errorf("invalid trash entry")
+	}
+
+	deletedAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(header, "Deleted: "))
+	if err != nil {
+		return TrashedNote{}, nil, fmt.Errorf("failed to parse deleted timestamp: %w", err)
+	}
+
+	note, err := parseNote(id, []byte(record))
+	if err != nil {
+		return TrashedNote{}, nil, err
+	}
+
+	return TrashedNote{Note: note, DeletedAt: deletedAt}, []byte(record), nil
+}
+
+func (nm *NoteManager) ListTrash() ([]TrashedNote, error) {
+	ids, err := nm.trash().List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var trashed []TrashedNote
+	for _, id := range ids {
+		entry, _, err := nm.loadTrashed(id)
+		if err != nil {
+			continue
+		}
+		trashed = append(trashed, entry)
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.Before(trashed[j].DeletedAt)
+	})
+
+	return trashed, nil
+}
+
+func (nm *NoteManager) UndeleteNote(id string) (*Note, error) {
+	entry, record, err := nm.loadTrashed(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = nm.update(func(tx *NoteManager) error {
+		if _, err := tx.store.Get(id); err == nil {
+			return fmt.Errorf("note %s already exists", id)
+		}
+
+		if err := tx.store.Put(id, record); err != nil {
+			return fmt.Errorf("failed to restore note: %w", err)
+		}
+		if err := tx.trash().Delete(id); err != nil {
+			return fmt.Errorf("failed to remove note from trash: %w", err)
+		}
+		return tx.indexNote(&entry.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry.Note, nil
+}
+
+// EmptyTrash permanently removes trashed notes, along with their revision
+// history, that were deleted more than olderThan ago. A zero olderThan
+// removes everything.
+func (nm *NoteManager) EmptyTrash(olderThan time.Duration) ([]TrashedNote, error) {
+	trashed, err := nm.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var removed []TrashedNote
+	for _, entry := range trashed {
+		if olderThan > 0 && entry.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := nm.trash().Delete(entry.ID); err != nil {
+			return removed, fmt.Errorf("failed to remove note %s from trash: %w", entry.ID, err)
+		}
+		if err := nm.history(entry.ID).Clear(); err != nil {
+			return removed, fmt.Errorf("failed to delete history of note %s: %w", entry.ID, err)
+		}
+		removed = append(removed, entry)
+	}
+
+	return removed, nil
+}
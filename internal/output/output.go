@@ -0,0 +1,119 @@
+// Package output writes records in the machine-readable formats selected with
+// --output: JSON, newline-delimited JSON, CSV and TSV.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	Text   Format = "text"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	CSV    Format = "csv"
+	TSV    Format = "tsv"
+)
+
+var Formats = []Format{Text, JSON, NDJSON, CSV, TSV}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if Format(strings.ToLower(s)) == f {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("invalid output format: %s (must be one of: %s)", s, strings.Join(names, ", "))
+}
+
+// Column is a CSV and TSV column: a header name and how to get the value of
+// a record.
+type Column[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+// List writes records: a JSON array, one JSON object per line, or a header
+// row followed by one row per record.
+func List[T any](w io.Writer, format Format, records []T, columns []Column[T]) error {
+	switch format {
+	case JSON:
+		if records == nil {
+			records = []T{}
+		}
+		return writeJSON(w, records)
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV, TSV:
+		return writeTable(w, format, records, columns)
+	}
+	return fmt.Errorf("output format %s does not write records", format)
+}
+
+// One writes a single record: a JSON object, or a header row and one row.
+func One[T any](w io.Writer, format Format, record T, columns []Column[T]) error {
+	if format == JSON {
+		return writeJSON(w, record)
+	}
+	return List(w, format, []T{record}, columns)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeTable[T any](w io.Writer, format Format, records []T, columns []Column[T]) error {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	rows := [][]string{header}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = column.Value(record)
+		}
+		rows = append(rows, row)
+	}
+
+	if format == TSV {
+		for _, row := range rows {
+			for i, value := range row {
+				row[i] = tsvEscaper.Replace(value)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// tsvEscaper keeps every TSV record on one line, escaping the characters
+// that would break it up.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
@@ -10,6 +10,8 @@ import (
 const (
 	NotesSubdir = "notes"
 	TasksSubdir = "tasks"
+
+	ConfigSubdir = "notes"
 )
 
 func GetDataDir(subdir string) (string, error) {
@@ -32,6 +34,26 @@ func GetDataDir(subdir string) (string, error) {
 	return baseDir, nil
 }
 
+func GetConfigDir(subdir string) (string, error) {
+	var baseDir string
+
+	if runtime.GOOS == "windows" {
+		baseDir = os.Getenv("APPDATA")
+	} else {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		baseDir = filepath.Join(homeDir, ".config")
+	}
+
+	if subdir != "" {
+		baseDir = filepath.Join(baseDir, subdir)
+	}
+
+	return baseDir, nil
+}
+
 func GetDataDirPerm() os.FileMode {
 	if runtime.GOOS == "windows" {
 		return 0755
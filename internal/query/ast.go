@@ -0,0 +1,193 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type andNode struct {
+	left, right Node
+}
+
+func (n *andNode) Match(r Record) bool {
+	return n.left.Match(r) && n.right.Match(r)
+}
+
+func (n *andNode) String() string {
+	return fmt.Sprintf("(%s AND %s)", n.left, n.right)
+}
+
+type orNode struct {
+	left, right Node
+}
+
+func (n *orNode) Match(r Record) bool {
+	return n.left.Match(r) || n.right.Match(r)
+}
+
+func (n *orNode) String() string {
+	return fmt.Sprintf("(%s OR %s)", n.left, n.right)
+}
+
+type notNode struct {
+	inner Node
+}
+
+func (n *notNode) Match(r Record) bool {
+	return !n.inner.Match(r)
+}
+
+func (n *notNode) String() string {
+	return fmt.Sprintf("NOT %s", n.inner)
+}
+
+// termNode matches a word or phrase anywhere in a record's text.
+type termNode struct {
+	text   string
+	phrase bool
+}
+
+func (n *termNode) Match(r Record) bool {
+	if m, ok := r.(TermMatcher); ok && !n.phrase {
+		if matched, ok := m.MatchTerm(n.text); ok {
+			return matched
+		}
+	}
+	return strings.Contains(strings.ToLower(r.Text()), strings.ToLower(n.text))
+}
+
+func (n *termNode) String() string {
+	if n.phrase {
+		return strconv.Quote(n.text)
+	}
+	return n.text
+}
+
+type predicateNode struct {
+	field string
+	kind  Kind
+	op    string
+	raw   string
+	none  bool
+
+	// from and to bound a Time value: a day-level value covers [from, to).
+	from, to time.Time
+	num      float64
+}
+
+func (n *predicateNode) String() string {
+	return fmt.Sprintf("%s%s%s", n.field, n.op, strconv.Quote(n.raw))
+}
+
+func (n *predicateNode) Match(r Record) bool {
+	value := r.Field(n.field)
+	if n.none {
+		return n.isUnset(value) != (n.op == "!=")
+	}
+
+	switch n.kind {
+	case Text:
+		return n.compareStrings(value.Str, func(s string) bool {
+			return strings.Contains(strings.ToLower(s), strings.ToLower(n.raw))
+		})
+	case Keyword:
+		return n.compareStrings(value.Str, func(s string) bool {
+			return strings.EqualFold(s, n.raw)
+		})
+	case Path:
+		return n.compareStrings(value.Str, func(s string) bool {
+			s, raw := strings.ToLower(s), strings.ToLower(n.raw)
+			return s == raw || strings.HasPrefix(s, raw+".")
+		})
+	case List:
+		found := false
+		for _, element := range value.List {
+			if strings.EqualFold(element, n.raw) {
+				found = true
+				break
+			}
+		}
+		return found != (n.op == "!=")
+	case Time:
+		if value.Time == nil {
+			return false
+		}
+		return n.compareTime(*value.Time)
+	case Number:
+		return n.compareNumber(value.Num)
+	}
+	return false
+}
+
+func (n *predicateNode) isUnset(value Value) bool {
+	switch n.kind {
+	case List:
+		return len(value.List) == 0
+	case Time:
+		return value.Time == nil
+	case Number:
+		return value.Num == 0
+	}
+	return value.Str == ""
+}
+
+// compareStrings applies ':' with contains, and '=' and '!=' as exact
+// case-insensitive comparisons.
+func (n *predicateNode) compareStrings(s string, contains func(string) bool) bool {
+	switch n.op {
+	case ":":
+		return contains(s)
+	case "=":
+		return strings.EqualFold(s, n.raw)
+	case "!=":
+		return !strings.EqualFold(s, n.raw)
+	}
+	return false
+}
+
+func (n *predicateNode) compareTime(t time.Time) bool {
+	switch n.op {
+	case ":", "=":
+		return n.inRange(t)
+	case "!=":
+		return !n.inRange(t)
+	case "<":
+		return t.Before(n.from)
+	case "<=":
+		return t.Before(n.from) || n.inRange(t)
+	case ">":
+		return !t.Before(n.from) && !n.inRange(t)
+	case ">=":
+		return !t.Before(n.from)
+	}
+	return false
+}
+
+// inRange reports whether t falls on the day a day-level value names, or is
+// exactly the instant of a value with a time of day.
+func (n *predicateNode) inRange(t time.Time) bool {
+	if n.from.Equal(n.to) {
+		return t.Equal(n.from)
+	}
+	return !t.Before(n.from) && t.Before(n.to)
+}
+
+func (n *predicateNode) compareNumber(x float64) bool {
+	switch n.op {
+	case ":", "=":
+		return x == n.num
+	case "!=":
+		return x != n.num
+	case "<":
+		return x < n.num
+	case "<=":
+		return x <= n.num
+	case ">":
+		return x > n.num
+	case ">=":
+		return x >= n.num
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return "\"" + t.text + "\""
+	}
+	return "'" + t.text + "'"
+}
+
+// lex splits input into tokens. Operators are only recognised directly after
+// a word, which makes "due<friday" a predicate while a value that follows an
+// operator may contain any character other than space and parentheses.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	afterOp := false
+
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i, end: i + 1})
+			i++
+			afterOp = false
+			continue
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i, end: i + 1})
+			i++
+			afterOp = false
+			continue
+		case c == '"':
+			tok, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = tok.end
+			afterOp = false
+			continue
+		}
+
+		if afterOp {
+			start := i
+			for i < len(input) && !unicode.IsSpace(rune(input[i])) && input[i] != '(' && input[i] != ')' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: input[start:i], pos: start, end: i})
+			afterOp = false
+			continue
+		}
+
+		if c == '-' && i+1 < len(input) && !unicode.IsSpace(rune(input[i+1])) && input[i+1] != ')' {
+			tokens = append(tokens, token{kind: tokNot, text: "-", pos: i, end: i + 1})
+			i++
+			continue
+		}
+
+		if op := lexOp(input[i:]); op != "" {
+			if len(tokens) == 0 || tokens[len(tokens)-1].kind != tokWord || tokens[len(tokens)-1].end != i {
+				return nil, &Error{Input: input, Pos: i, End: i + len(op), Msg: "operator '" + op + "' must follow a field name"}
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i, end: i + len(op)})
+			i += len(op)
+			afterOp = true
+			continue
+		}
+		if c == '!' {
+			return nil, &Error{Input: input, Pos: i, End: i + 1, Msg: "unexpected '!' (use NOT or '-' to negate)"}
+		}
+
+		start := i
+		for i < len(input) && !unicode.IsSpace(rune(input[i])) && !strings.ContainsRune("()\":=!<>", rune(input[i])) {
+			i++
+		}
+		word := input[start:i]
+		kind := tokWord
+		switch word {
+		case "AND":
+			kind = tokAnd
+		case "OR":
+			kind = tokOr
+		case "NOT":
+			kind = tokNot
+		}
+		tokens = append(tokens, token{kind: kind, text: word, pos: start, end: i})
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(input), end: len(input)})
+	return tokens, nil
+}
+
+func lexOp(s string) string {
+	for _, op := range []string{"!=", "<=", ">=", ":", "=", "<", ">"} {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func lexString(input string, start int) (token, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 < len(input) {
+				i++
+				sb.WriteByte(input[i])
+			}
+		case '"':
+			return token{kind: tokString, text: sb.String(), pos: start, end: i + 1}, nil
+		default:
+			sb.WriteByte(input[i])
+		}
+	}
+	return token{}, &Error{Input: input, Pos: start, End: len(input), Msg: "unterminated quoted phrase"}
+}
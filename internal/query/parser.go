@@ -0,0 +1,165 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/dateparse"
+)
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+	schema Schema
+	now    time.Time
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorAt(tok token, msg string) error {
+	return &Error{Input: p.input, Pos: tok.pos, End: tok.end, Msg: msg}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokWord, tokString, tokLParen, tokNot:
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorAt(tok, "missing ')' for this '('")
+		}
+		p.next()
+		return inner, nil
+	case tokString:
+		return &termNode{text: tok.text, phrase: true}, nil
+	case tokWord:
+		if p.peek().kind == tokOp {
+			return p.parsePredicate(tok)
+		}
+		return &termNode{text: tok.text}, nil
+	case tokEOF:
+		return nil, p.errorAt(tok, "unexpected end of query")
+	}
+	return nil, p.errorAt(tok, "unexpected "+tok.describe())
+}
+
+func (p *parser) parsePredicate(field token) (Node, error) {
+	name := strings.ToLower(field.text)
+	kind, ok := p.schema[name]
+	if !ok {
+		return nil, p.errorAt(field, fmt.Sprintf("unknown field %q (fields: %s)", field.text, p.fieldNames()))
+	}
+
+	op := p.next()
+	value := p.next()
+	if value.kind != tokWord && value.kind != tokString {
+		return nil, p.errorAt(op, fmt.Sprintf("expected a value after '%s'", op.text))
+	}
+
+	pred := &predicateNode{field: name, kind: kind, op: op.text, raw: value.text}
+	ordering := op.text == "<" || op.text == "<=" || op.text == ">" || op.text == ">="
+	if ordering && kind != Time && kind != Number {
+		return nil, p.errorAt(op, fmt.Sprintf("operator '%s' cannot be used with field %q", op.text, name))
+	}
+
+	if strings.EqualFold(value.text, "none") && !ordering {
+		pred.none = true
+		return pred, nil
+	}
+
+	switch kind {
+	case Time:
+		t, err := dateparse.Parse(value.text, p.now)
+		if err != nil {
+			return nil, p.errorAt(value, err.Error())
+		}
+		pred.from = t
+		pred.to = t
+		if t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())) {
+			pred.to = t.AddDate(0, 0, 1)
+		}
+	case Number:
+		n, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, p.errorAt(value, fmt.Sprintf("field %q needs a number", name))
+		}
+		pred.num = n
+	}
+
+	return pred, nil
+}
+
+func (p *parser) fieldNames() string {
+	names := make([]string, 0, len(p.schema))
+	for name := range p.schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
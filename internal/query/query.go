@@ -0,0 +1,166 @@
+// Package query implements the search language shared by the note and task
+// list and search commands.
+//
+// A query is a sequence of terms combined with AND (also implied between
+// adjacent terms), OR and NOT (or a leading '-'), grouped with parentheses.
+// A term is a bare word or quoted phrase, matched case-insensitively against
+// a record's text, or a field predicate such as status:open, tag:work,
+// due<2026-11-01, created>-7d or name:"foo bar". Operators are ':' (contains
+// or matches), '=', '!=', '<', '<=', '>' and '>='; which ones apply depends
+// on the field's Kind.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind describes how a field's values are compared.
+type Kind int
+
+const (
+	// Text fields match ':' as a case-insensitive substring.
+	Text Kind = iota
+	// Keyword fields only match whole values, case-insensitively.
+	Keyword
+	// Path fields hold dotted names; ':' also matches descendants, so
+	// project:work matches work.backend.
+	Path
+	// List fields match when any element equals the value.
+	List
+	// Time fields accept date expressions and support ordering. A value
+	// without a time of day covers the whole day.
+	Time
+	// Number fields support ordering.
+	Number
+)
+
+// Schema maps field names to their kinds.
+type Schema map[string]Kind
+
+// Value is a field value of a record; which member is used depends on the
+// field's Kind. A nil Time means the field is unset.
+type Value struct {
+	Str  string
+	List []string
+	Time *time.Time
+	Num  float64
+}
+
+// Record is something a query can be evaluated against.
+type Record interface {
+	// Text is searched by bare words and quoted phrases.
+	Text() string
+	Field(name string) Value
+}
+
+// TermMatcher may be implemented by a Record to decide bare words itself,
+// for example from a search index. When ok is false the word is matched
+// against Text as usual; quoted phrases always are.
+type TermMatcher interface {
+	MatchTerm(word string) (matched, ok bool)
+}
+
+// Node is a parsed query.
+type Node interface {
+	Match(r Record) bool
+	String() string
+}
+
+// Error is a syntax or validation error in a query, pointing at the
+// offending part of the input.
+type Error struct {
+	Input string
+	Pos   int
+	End   int
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	width := e.End - e.Pos
+	if width < 1 {
+		width = 1
+	}
+	return fmt.Sprintf("invalid query at column %d: %s\n  %s\n  %s%s",
+		e.Pos+1, e.Msg, e.Input, strings.Repeat(" ", e.Pos), strings.Repeat("^", width))
+}
+
+// Parse parses input against schema, resolving date expressions relative to
+// now. An empty query yields a nil Node; use Match to treat it as matching
+// everything.
+func Parse(input string, schema Schema, now time.Time) (Node, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: input, tokens: tokens, schema: schema, now: now}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorAt(tok, "unexpected "+tok.describe())
+	}
+	return node, nil
+}
+
+// And combines two queries, either of which may be nil.
+func And(a, b Node) Node {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return &andNode{left: a, right: b}
+}
+
+// Match reports whether r satisfies n; a nil query matches everything.
+func Match(n Node, r Record) bool {
+	return n == nil || n.Match(r)
+}
+
+// Terms returns the words and phrases of n that are not negated, which a
+// matching record may contain.
+func Terms(n Node) []string {
+	var terms []string
+	var walk func(Node, bool)
+	walk = func(n Node, negated bool) {
+		switch n := n.(type) {
+		case *andNode:
+			walk(n.left, negated)
+			walk(n.right, negated)
+		case *orNode:
+			walk(n.left, negated)
+			walk(n.right, negated)
+		case *notNode:
+			walk(n.inner, !negated)
+		case *termNode:
+			if !negated {
+				terms = append(terms, n.text)
+			}
+		}
+	}
+	walk(n, false)
+	return terms
+}
+
+// Required returns the bare words every record matching n must contain:
+// those joined to the rest of the query by AND alone.
+func Required(n Node) []string {
+	switch n := n.(type) {
+	case *andNode:
+		return append(Required(n.left), Required(n.right)...)
+	case *termNode:
+		if !n.phrase {
+			return []string{n.text}
+		}
+	}
+	return nil
+}
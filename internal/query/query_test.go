@@ -0,0 +1,150 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"name":     Text,
+	"status":   Keyword,
+	"project":  Path,
+	"tag":      List,
+	"due":      Time,
+	"priority": Number,
+}
+
+type testRecord struct {
+	text   string
+	fields map[string]Value
+}
+
+func (r testRecord) Text() string { return r.text }
+
+func (r testRecord) Field(name string) Value { return r.fields[name] }
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC)
+	record := testRecord{
+		text: "Deploy the API\nRoll out to staging first",
+		fields: map[string]Value{
+			"name":     {Str: "Deploy the API"},
+			"status":   {Str: "open"},
+			"project":  {Str: "work.backend"},
+			"tag":      {List: []string{"urgent", "ops"}},
+			"due":      {Time: &due},
+			"priority": {Num: 2},
+		},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"deploy", true},
+		{"DEPLOY staging", true},
+		{"deploy production", false},
+		{"deploy OR production", true},
+		{"-staging", false},
+		{"NOT production", true},
+		{`"out to staging"`, true},
+		{`"staging to out"`, false},
+		{"(production OR staging) AND api", true},
+		{"name:api", true},
+		{"name:staging", false},
+		{"name=api", false},
+		{`name="deploy the api"`, true},
+		{"status:open", true},
+		{"status:op", false},
+		{"status!=open", false},
+		{"project:work", true},
+		{"project:wor", false},
+		{"project=work", false},
+		{"tag:ops", true},
+		{"tag:op", false},
+		{"tag!=ops", false},
+		{"tag:none", false},
+		{"due:2026-10-20", true},
+		{"due<2026-10-20", false},
+		{"due<=2026-10-20", true},
+		{"due>tomorrow", true},
+		{"due>2026-10-20", false},
+		{"due>=+6d", true},
+		{"due:none", false},
+		{"due!=none", true},
+		{"priority>1", true},
+		{"priority<=1", false},
+		{"priority=2", true},
+		{"status:open tag:urgent -project:home", true},
+	}
+	for _, tt := range tests {
+		node, err := Parse(tt.query, testSchema, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		if got := Match(node, record); got != tt.want {
+			t.Errorf("Match(%q) = %v; want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"(deploy", 0},
+		{"deploy )", 7},
+		{"owner:bob", 0},
+		{"status<open", 6},
+		{"due:someday", 4},
+		{"priority>high", 9},
+		{"deploy AND", 10},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query, testSchema, now)
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Errorf("Parse(%q) error = %v; want a *query.Error", tt.query, err)
+			continue
+		}
+		if qerr.Pos != tt.pos {
+			t.Errorf("Parse(%q) error at column %d; want %d (%s)", tt.query, qerr.Pos+1, tt.pos+1, qerr.Msg)
+		}
+	}
+}
+
+func TestTermsAndRequired(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		query    string
+		terms    []string
+		required []string
+	}{
+		{"deploy api", []string{"deploy", "api"}, []string{"deploy", "api"}},
+		{`deploy "roll out"`, []string{"deploy", "roll out"}, []string{"deploy"}},
+		{"deploy OR release", []string{"deploy", "release"}, nil},
+		{"deploy -staging", []string{"deploy"}, []string{"deploy"}},
+		{"NOT (deploy OR release) api", []string{"api"}, []string{"api"}},
+		{"status:open api", []string{"api"}, []string{"api"}},
+	}
+	for _, tt := range tests {
+		node, err := Parse(tt.query, testSchema, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		if got := Terms(node); !reflect.DeepEqual(got, tt.terms) {
+			t.Errorf("Terms(%q) = %q; want %q", tt.query, got, tt.terms)
+		}
+		if got := Required(node); !reflect.DeepEqual(got, tt.required) {
+			t.Errorf("Required(%q) = %q; want %q", tt.query, got, tt.required)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package record
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Parse splits a stored record into its "Key: value" header fields and its
+// content. The header runs up to and including the Name field, which is
+// always written last; everything after it is content.
+func Parse(data []byte) ([]Field, string, error) {
+	lines := strings.Split(string(data), "\n")
+
+	var fields []Field
+	for i, line := range lines {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			break
+		}
+		fields = append(fields, Field{Key: key, Value: strings.TrimPrefix(value, " ")})
+		if key == "Name" {
+			return fields, strings.Join(lines[i+1:], "\n"), nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("missing Name header")
+}
+
+// Get returns the value of the first field with the given key.
+func Get(fields []Field, key string) string {
+	for _, f := range fields {
+		if f.Key == key {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// All returns the values of every field with the given key, in order.
+func All(fields []Field, key string) []string {
+	var values []string
+	for _, f := range fields {
+		if f.Key == key {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
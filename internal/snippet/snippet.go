@@ -0,0 +1,233 @@
+// Package snippet extracts the lines around search matches so results can
+// show why they matched.
+package snippet
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// lineWidth is the most runes of a line shown; longer lines are cut
+	// around their first match.
+	lineWidth = 160
+	// lead is how many runes before the first match a cut line keeps.
+	lead     = 40
+	ellipsis = "…"
+
+	highlightOn  = "\x1b[1;31m"
+	highlightOff = "\x1b[0m"
+)
+
+// Match is a byte range within a line.
+type Match struct {
+	Start, End int
+}
+
+type Line struct {
+	// Number is the 1-based line number within the searched text.
+	Number  int
+	Text    string
+	Matches []Match
+}
+
+// Snippet is a run of consecutive lines: the matching lines with context
+// around them.
+type Snippet struct {
+	Lines []Line
+}
+
+// Matcher returns the ranges of line that match a search, in order and
+// without overlaps.
+type Matcher func(line string) []Match
+
+// Find returns up to max snippets of text around lines in which match finds
+// something, with context lines before and after each. Snippets whose
+// context would overlap are merged.
+func Find(text string, match Matcher, context, max int) []Snippet {
+	if context < 0 {
+		context = 0
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	var snippets []Snippet
+	end := -1
+	for i, line := range lines {
+		if len(snippets) == max && i > end {
+			break
+		}
+		matches := match(line)
+		if len(matches) == 0 {
+			continue
+		}
+
+		from := i - context
+		if from < 0 {
+			from = 0
+		}
+		if len(snippets) > 0 && from <= end+1 {
+			// Extend the current snippet.
+			from = end + 1
+		} else if len(snippets) == max {
+			break
+		} else {
+			snippets = append(snippets, Snippet{})
+		}
+
+		to := i + context
+		if to >= len(lines) {
+			to = len(lines) - 1
+		}
+		last := &snippets[len(snippets)-1]
+		for j := from; j <= to; j++ {
+			last.Lines = append(last.Lines, Line{Number: j + 1, Text: lines[j]})
+		}
+		if to > end {
+			end = to
+		}
+		for j := range last.Lines {
+			if last.Lines[j].Number == i+1 {
+				last.Lines[j].Matches = matches
+			}
+		}
+	}
+
+	for _, s := range snippets {
+		for j := range s.Lines {
+			s.Lines[j] = clip(s.Lines[j])
+		}
+	}
+	return snippets
+}
+
+// Words matches the whole words of a line, split into runs of letters and
+// digits as the search index splits text, that match accepts.
+func Words(match func(word string) bool) Matcher {
+	return func(line string) []Match {
+		var matches []Match
+		start := -1
+		for i, r := range line {
+			switch {
+			case isWordRune(r) && start < 0:
+				start = i
+			case !isWordRune(r) && start >= 0:
+				if match(line[start:i]) {
+					matches = append(matches, Match{Start: start, End: i})
+				}
+				start = -1
+			}
+		}
+		if start >= 0 && match(line[start:]) {
+			matches = append(matches, Match{Start: start, End: len(line)})
+		}
+		return matches
+	}
+}
+
+// Regexp matches re anywhere in a line.
+func Regexp(re *regexp.Regexp) Matcher {
+	return func(line string) []Match {
+		var matches []Match
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			if loc[1] > loc[0] {
+				matches = append(matches, Match{Start: loc[0], End: loc[1]})
+			}
+		}
+		return matches
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// clip shortens a line longer than lineWidth runes to a window starting a
+// little before its first match, marking cut ends with an ellipsis.
+func clip(line Line) Line {
+	if utf8.RuneCountInString(line.Text) <= lineWidth {
+		return line
+	}
+
+	from := 0
+	if len(line.Matches) > 0 {
+		from = line.Matches[0].Start
+		for i := 0; i < lead && from > 0; i++ {
+			_, size := utf8.DecodeLastRuneInString(line.Text[:from])
+			from -= size
+		}
+	}
+	to := from
+	for i := 0; i < lineWidth && to < len(line.Text); i++ {
+		_, size := utf8.DecodeRuneInString(line.Text[to:])
+		to += size
+	}
+
+	text := line.Text[from:to]
+	shift := -from
+	if from > 0 {
+		text = ellipsis + text
+		shift += len(ellipsis)
+	}
+	if to < len(line.Text) {
+		text += ellipsis
+	}
+
+	clipped := Line{Number: line.Number, Text: text}
+	for _, m := range line.Matches {
+		if m.Start >= from && m.End <= to {
+			clipped.Matches = append(clipped.Matches, Match{Start: m.Start + shift, End: m.End + shift})
+		}
+	}
+	return clipped
+}
+
+// Format renders snippets grep-style, one line per row prefixed with indent
+// and its line number: "12: " for matching lines and "12- " for context.
+// Snippets are separated by "--". With color set, matches are highlighted
+// with ANSI escapes.
+func Format(snippets []Snippet, indent string, color bool) string {
+	width := 0
+	for _, s := range snippets {
+		for _, line := range s.Lines {
+			if w := len(strconv.Itoa(line.Number)); w > width {
+				width = w
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, s := range snippets {
+		if i > 0 {
+			fmt.Fprintf(&sb, "%s--\n", indent)
+		}
+		for _, line := range s.Lines {
+			sep := "-"
+			if len(line.Matches) > 0 {
+				sep = ":"
+			}
+			fmt.Fprintf(&sb, "%s%*d%s %s\n", indent, width, line.Number, sep, highlight(line, color))
+		}
+	}
+	return sb.String()
+}
+
+func highlight(line Line, color bool) string {
+	if !color || len(line.Matches) == 0 {
+		return line.Text
+	}
+	var sb strings.Builder
+	prev := 0
+	for _, m := range line.Matches {
+		sb.WriteString(line.Text[prev:m.Start])
+		sb.WriteString(highlightOn)
+		sb.WriteString(line.Text[m.Start:m.End])
+		sb.WriteString(highlightOff)
+		prev = m.End
+	}
+	sb.WriteString(line.Text[prev:])
+	return sb.String()
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wltechblog/notes/internal/platform"
+)
+
+const (
+	recordExt   = ".txt"
+	counterFile = ".counter"
+	lockName    = ".lock"
+)
+
+type FileStore struct {
+	baseDir string
+	// locked is set on the view handed to Update, whose caller already
+	// holds the directory lock.
+	locked bool
+}
+
+func NewFileStore(baseDir string) (*FileStore, error) {
+	fs := &FileStore{baseDir: baseDir}
+	if err := fs.ensureDir(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (fs *FileStore) Clear() error {
+	if _, err := os.Stat(fs.baseDir); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.RemoveAll(fs.baseDir); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(fs.baseDir))
+}
+
+func (fs *FileStore) ensureDir() error {
+	if err := os.MkdirAll(fs.baseDir, platform.GetDataDirPerm()); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return nil
+}
+
+func (fs *FileStore) Sub(name string) Store {
+	return &FileStore{baseDir: filepath.Join(fs.baseDir, name)}
+}
+
+func (fs *FileStore) path(id string) string {
+	return filepath.Join(fs.baseDir, id+recordExt)
+}
+
+func (fs *FileStore) lock() (func(), error) {
+	if fs.locked {
+		return func() {}, nil
+	}
+	if err := fs.ensureDir(); err != nil {
+		return nil, err
+	}
+	return lockFile(filepath.Join(fs.baseDir, lockName))
+}
+
+func (fs *FileStore) Update(fn func(tx Store) error) error {
+	unlock, err := fs.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn(&FileStore{baseDir: fs.baseDir, locked: true})
+}
+
+func (fs *FileStore) exists(id string) (bool, error) {
+	_, err := os.Stat(fs.path(id))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (fs *FileStore) Get(id string) ([]byte, error) {
+	data, err := os.ReadFile(fs.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (fs *FileStore) Put(id string, data []byte) error {
+	unlock, err := fs.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeFileAtomic(fs.path(id), data, platform.GetDataFilePerm())
+}
+
+func (fs *FileStore) List() ([]string, error) {
+	entries, err := os.ReadDir(fs.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), recordExt) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(entry.Name(), recordExt))
+	}
+	SortIDs(ids)
+
+	return ids, nil
+}
+
+func (fs *FileStore) Delete(id string) error {
+	if _, err := os.Stat(fs.baseDir); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	unlock, err := fs.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(fs.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return err
+	}
+	return syncDir(fs.baseDir)
+}
+
+func (fs *FileStore) NextID() (string, error) {
+	unlock, err := fs.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	counterPath := filepath.Join(fs.baseDir, counterFile)
+
+	data, err := os.ReadFile(counterPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read counter file: %w", err)
+		}
+		data = []byte("0")
+	}
+
+	currentID, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse counter: %w", err)
+	}
+
+	nextID := currentID + 1
+	for {
+		taken, err := fs.exists(strconv.FormatInt(nextID, 10))
+		if err != nil {
+			return "", fmt.Errorf("failed to check for existing record: %w", err)
+		}
+		if !taken {
+			break
+		}
+		nextID++
+	}
+
+	if err := writeFileAtomic(counterPath, []byte(strconv.FormatInt(nextID, 10)), platform.GetDataFilePerm()); err != nil {
+		return "", fmt.Errorf("failed to write counter: %w", err)
+	}
+
+	return strconv.FormatInt(nextID, 10), nil
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"sort"
+	"strconv"
+)
+
+// SortIDs orders numeric IDs by value, so "10" sorts after "2". Non-numeric
+// IDs sort lexically after all numeric ones.
+func SortIDs(ids []string) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		return LessID(ids[i], ids[j])
+	})
+}
+
+func LessID(a, b string) bool {
+	na, errA := strconv.ParseInt(a, 10, 64)
+	nb, errB := strconv.ParseInt(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return na < nb
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	default:
+		return a < b
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wltechblog/notes/internal/platform"
+)
+
+const (
+	lockRetryInterval = 10 * time.Millisecond
+	lockStaleAfter    = 30 * time.Second
+)
+
+// lockFile falls back to an exclusively created lock file on platforms
+// without flock. A lock left behind by a crashed process is broken once it
+// is older than lockStaleAfter.
+func lockFile(path string) (func(), error) {
+	path += ".excl"
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, platform.GetDataFilePerm())
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			os.Remove(path)
+			continue
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+type MemoryStore struct {
+	// write is the store lock held by Put, Delete, NextID and Update; mu
+	// only guards the maps.
+	write   sync.Mutex
+	mu      sync.Mutex
+	records map[string][]byte
+	subs    map[string]*MemoryStore
+	counter int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string][]byte),
+		subs:    make(map[string]*MemoryStore),
+	}
+}
+
+func (ms *MemoryStore) Sub(name string) Store {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	sub, ok := ms.subs[name]
+	if !ok {
+		sub = NewMemoryStore()
+		ms.subs[name] = sub
+	}
+	return sub
+}
+
+func (ms *MemoryStore) Clear() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.records = make(map[string][]byte)
+	ms.subs = make(map[string]*MemoryStore)
+	ms.counter = 0
+	return nil
+}
+
+func (ms *MemoryStore) Get(id string) ([]byte, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	data, ok := ms.records[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (ms *MemoryStore) Put(id string, data []byte) error {
+	ms.write.Lock()
+	defer ms.write.Unlock()
+
+	return ms.put(id, data)
+}
+
+func (ms *MemoryStore) put(id string, data []byte) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.records[id] = append([]byte(nil), data...)
+	return nil
+}
+
+func (ms *MemoryStore) List() ([]string, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ids := make([]string, 0, len(ms.records))
+	for id := range ms.records {
+		ids = append(ids, id)
+	}
+	SortIDs(ids)
+	return ids, nil
+}
+
+func (ms *MemoryStore) Delete(id string) error {
+	ms.write.Lock()
+	defer ms.write.Unlock()
+
+	return ms.delete(id)
+}
+
+func (ms *MemoryStore) delete(id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.records[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	delete(ms.records, id)
+	return nil
+}
+
+func (ms *MemoryStore) NextID() (string, error) {
+	ms.write.Lock()
+	defer ms.write.Unlock()
+
+	return ms.nextID()
+}
+
+func (ms *MemoryStore) nextID() (string, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.counter++
+	for {
+		if _, taken := ms.records[strconv.FormatInt(ms.counter, 10)]; !taken {
+			break
+		}
+		ms.counter++
+	}
+	return strconv.FormatInt(ms.counter, 10), nil
+}
+
+func (ms *MemoryStore) Update(fn func(tx Store) error) error {
+	ms.write.Lock()
+	defer ms.write.Unlock()
+
+	return fn(memoryTx{ms})
+}
+
+// memoryTx is the view of a MemoryStore handed to Update. Its writes skip
+// the store lock, which the caller already holds.
+type memoryTx struct {
+	*MemoryStore
+}
+
+func (tx memoryTx) Put(id string, data []byte) error {
+	return tx.put(id, data)
+}
+
+func (tx memoryTx) Delete(id string) error {
+	return tx.delete(id)
+}
+
+func (tx memoryTx) NextID() (string, error) {
+	return tx.nextID()
+}
+
+func (tx memoryTx) Update(fn func(tx Store) error) error {
+	return fn(tx)
+}
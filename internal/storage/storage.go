@@ -0,0 +1,24 @@
+package storage
+
+import "errors"
+
+var ErrNotFound = errors.New("record not found")
+
+// Store persists raw record data keyed by ID. Implementations hand out
+// monotonically increasing numeric IDs through NextID and List returns IDs
+// in numeric order. Sub returns a nested namespace with its own records and
+// counter, used for auxiliary data such as revision history; nested
+// namespaces never appear in List. Clear removes every record and nested
+// namespace. Update holds the store's write lock while fn runs, so a
+// read-modify-write through tx cannot interleave with another writer; tx
+// is the same store with the lock already taken.
+type Store interface {
+	Get(id string) ([]byte, error)
+	Put(id string, data []byte) error
+	List() ([]string, error)
+	Delete(id string) error
+	NextID() (string, error)
+	Sub(name string) Store
+	Clear() error
+	Update(fn func(tx Store) error) error
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store {
+		return NewMemoryStore()
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store {
+		fs, err := NewFileStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fs
+	})
+}
+
+// testStore checks the behaviour every Store implementation must share.
+func testStore(t *testing.T, newStore func(t *testing.T) Store) {
+	t.Run("PutGetDelete", func(t *testing.T) {
+		s := newStore(t)
+		if err := s.Put("1", []byte("one")); err != nil {
+			t.Fatal(err)
+		}
+		data, err := s.Get("1")
+		if err != nil || string(data) != "one" {
+			t.Fatalf("Get = %q, %v; want %q", data, err, "one")
+		}
+
+		if err := s.Put("1", []byte("uno")); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := s.Get("1"); string(data) != "uno" {
+			t.Fatalf("Get after overwrite = %q; want %q", data, "uno")
+		}
+
+		if err := s.Delete("1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Get("1"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Get after Delete: err = %v; want ErrNotFound", err)
+		}
+		if err := s.Delete("1"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("second Delete: err = %v; want ErrNotFound", err)
+		}
+	})
+
+	t.Run("NextID", func(t *testing.T) {
+		s := newStore(t)
+		for _, want := range []string{"1", "2"} {
+			id, err := s.NextID()
+			if err != nil || id != want {
+				t.Fatalf("NextID = %q, %v; want %q", id, err, want)
+			}
+		}
+
+		// Records written under IDs the counter has not reached yet are
+		// skipped rather than overwritten.
+		for _, id := range []string{"3", "4", "6"} {
+			if err := s.Put(id, []byte(id)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, want := range []string{"5", "7"} {
+			id, err := s.NextID()
+			if err != nil || id != want {
+				t.Fatalf("NextID = %q, %v; want %q", id, err, want)
+			}
+		}
+	})
+
+	t.Run("ListOrder", func(t *testing.T) {
+		s := newStore(t)
+		if ids, err := s.List(); err != nil || len(ids) != 0 {
+			t.Fatalf("List on empty store = %v, %v; want none", ids, err)
+		}
+
+		for _, id := range []string{"10", "b", "2", "a", "1"} {
+			if err := s.Put(id, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ids, err := s.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"1", "2", "10", "a", "b"}; !reflect.DeepEqual(ids, want) {
+			t.Fatalf("List = %v; want %v", ids, want)
+		}
+	})
+
+	t.Run("Sub", func(t *testing.T) {
+		s := newStore(t)
+		if err := s.Put("1", []byte("parent")); err != nil {
+			t.Fatal(err)
+		}
+		sub := s.Sub("history")
+		if err := sub.Put("1", []byte("child")); err != nil {
+			t.Fatal(err)
+		}
+
+		if data, _ := s.Get("1"); string(data) != "parent" {
+			t.Fatalf("parent record = %q; want %q", data, "parent")
+		}
+		if data, _ := s.Sub("history").Get("1"); string(data) != "child" {
+			t.Fatalf("sub record = %q; want %q", data, "child")
+		}
+		if ids, _ := s.List(); !reflect.DeepEqual(ids, []string{"1"}) {
+			t.Fatalf("List = %v; want only the parent's record", ids)
+		}
+
+		// The nested namespace keeps its own counter.
+		if id, err := sub.NextID(); err != nil || id != "2" {
+			t.Fatalf("sub NextID = %q, %v; want %q", id, err, "2")
+		}
+		if id, err := s.NextID(); err != nil || id != "2" {
+			t.Fatalf("parent NextID = %q, %v; want %q", id, err, "2")
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		s := newStore(t)
+		for _, id := range []string{"1", "2"} {
+			if err := s.Put(id, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Sub("trash").Put("3", nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.NextID(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if ids, err := s.List(); err != nil || len(ids) != 0 {
+			t.Fatalf("List after Clear = %v, %v; want none", ids, err)
+		}
+		if ids, err := s.Sub("trash").List(); err != nil || len(ids) != 0 {
+			t.Fatalf("sub List after Clear = %v, %v; want none", ids, err)
+		}
+		if id, err := s.NextID(); err != nil || id != "1" {
+			t.Fatalf("NextID after Clear = %q, %v; want %q", id, err, "1")
+		}
+		if err := s.Clear(); err != nil {
+			t.Fatalf("Clear on empty store: %v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		s := newStore(t)
+		err := s.Update(func(tx Store) error {
+			id, err := tx.NextID()
+			if err != nil {
+				return err
+			}
+			if err := tx.Put(id, []byte("in tx")); err != nil {
+				return err
+			}
+			// Nested updates run on the lock already held.
+			return tx.Update(func(tx Store) error {
+				return tx.Put("2", []byte("nested"))
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids, _ := s.List(); !reflect.DeepEqual(ids, []string{"1", "2"}) {
+			t.Fatalf("List after Update = %v; want [1 2]", ids)
+		}
+
+		failed := errors.New("failed")
+		if err := s.Update(func(Store) error { return failed }); !errors.Is(err, failed) {
+			t.Fatalf("Update error = %v; want %v", err, failed)
+		}
+		// The lock is released after an error.
+		if err := s.Put("3", nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("UpdateSerializes", func(t *testing.T) {
+		s := newStore(t)
+		if err := s.Put("count", []byte("0")); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 5; j++ {
+					err := s.Update(func(tx Store) error {
+						data, err := tx.Get("count")
+						if err != nil {
+							return err
+						}
+						n, err := strconv.Atoi(string(data))
+						if err != nil {
+							return err
+						}
+						return tx.Put("count", []byte(strconv.Itoa(n+1)))
+					})
+					if err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if data, _ := s.Get("count"); string(data) != "40" {
+			t.Fatalf("count = %s; want 40", data)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package tags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Normalize lowercases a tag and strips a leading '+' and surrounding space.
+func Normalize(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+}
+
+func Validate(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("empty tag")
+	}
+	if strings.ContainsAny(tag, ", \t\n") {
+		return fmt.Errorf("invalid tag: %q (tags cannot contain spaces or commas)", tag)
+	}
+	return nil
+}
+
+// Parse reads a comma-separated Tags header value.
+func Parse(value string) []string {
+	var result []string
+	for _, tag := range strings.Split(value, ",") {
+		result = Add(result, tag)
+	}
+	return result
+}
+
+func Format(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// Split separates "+tag" arguments from the rest of a command line.
+func Split(args []string) ([]string, []string, error) {
+	var tagList, rest []string
+	for _, arg := range args {
+		if len(arg) > 1 && strings.HasPrefix(arg, "+") {
+			tag := Normalize(arg)
+			if err := Validate(tag); err != nil {
+				return nil, nil, err
+			}
+			tagList = Add(tagList, tag)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return tagList, rest, nil
+}
+
+// Add returns tags with tag inserted, keeping the list sorted and unique.
+func Add(tags []string, tag string) []string {
+	tag = Normalize(tag)
+	if tag == "" || Has(tags, tag) {
+		return tags
+	}
+	result := append(append([]string(nil), tags...), tag)
+	sort.Strings(result)
+	return result
+}
+
+func Remove(tags []string, tag string) []string {
+	tag = Normalize(tag)
+	var result []string
+	for _, t := range tags {
+		if t != tag {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func Has(tags []string, tag string) bool {
+	tag = Normalize(tag)
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether tags contains every tag in include and none in
+// exclude.
+func Match(tags, include, exclude []string) bool {
+	for _, tag := range include {
+		if !Has(tags, tag) {
+			return false
+		}
+	}
+	for _, tag := range exclude {
+		if Has(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+)
+
+// blockerIndex maps each task ID to the IDs of the tasks it depends on that
+// are not yet in a terminal status. Dependencies on tasks that no longer
+// exist do not block.
+func blockerIndex(taskList []Task) map[string][]string {
+	open := make(map[string]bool, len(taskList))
+	for _, task := range taskList {
+		open[task.ID] = !task.IsTerminal()
+	}
+
+	blockers := make(map[string][]string)
+	for _, task := range taskList {
+		for _, dep := range task.DependsOn {
+			if open[dep] {
+				blockers[task.ID] = append(blockers[task.ID], dep)
+			}
+		}
+	}
+	return blockers
+}
+
+// OpenBlockers returns, for every task that is waiting on others, the IDs of
+// the open tasks it depends on.
+func (tm *TaskManager) OpenBlockers() (map[string][]string, error) {
+	taskList, err := tm.ListTasks(Filter{})
+	if err != nil {
+		return nil, err
+	}
+	return blockerIndex(taskList), nil
+}
+
+func (tm *TaskManager) AddDependencies(id string, deps ...string) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		taskList, err := tm.ListTasks(Filter{})
+		if err != nil {
+			return err
+		}
+		graph := make(map[string][]string, len(taskList))
+		for _, t := range taskList {
+			graph[t.ID] = t.DependsOn
+		}
+
+		for _, dep := range deps {
+			if dep == id {
+				return fmt.Errorf("task %s cannot depend on itself", id)
+			}
+			if _, ok := graph[dep]; !ok {
+				return fmt.Errorf("task not found: %s", dep)
+			}
+			if dependsOn(graph, dep, id) {
+				return fmt.Errorf("task %s already depends on task %s; adding this dependency would create a cycle", dep, id)
+			}
+			if !containsID(task.DependsOn, dep) {
+				task.DependsOn = append(task.DependsOn, dep)
+				graph[id] = task.DependsOn
+			}
+		}
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (tm *TaskManager) RemoveDependencies(id string, deps ...string) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		var remaining []string
+		for _, dep := range task.DependsOn {
+			if !containsID(deps, dep) {
+				remaining = append(remaining, dep)
+			}
+		}
+		task.DependsOn = remaining
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// dependsOn reports whether from reaches to by following dependencies,
+// including the trivial case from == to.
+func dependsOn(graph map[string][]string, from, to string) bool {
+	seen := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		stack = append(stack, graph[id]...)
+	}
+	return false
+}
+
+func containsID(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
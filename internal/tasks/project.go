@@ -0,0 +1,74 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type ProjectSummary struct {
+	Name      string `json:"name"`
+	Open      int    `json:"open"`
+	Completed int    `json:"completed"`
+}
+
+// ValidateProject checks a dotted project path such as "work.backend.auth".
+func ValidateProject(project string) error {
+	if project == "" {
+		return nil
+	}
+	for _, segment := range strings.Split(project, ".") {
+		if segment == "" || strings.ContainsAny(segment, " \t\n,") {
+			return fmt.Errorf("invalid project: %q (use dot-separated names without spaces, e.g. work.backend)", project)
+		}
+	}
+	return nil
+}
+
+// InProject reports whether project is node itself or one of its descendants.
+func InProject(project, node string) bool {
+	return project == node || strings.HasPrefix(project, node+".")
+}
+
+// projectAncestors returns every node on the path to project, root first.
+func projectAncestors(project string) []string {
+	segments := strings.Split(project, ".")
+	nodes := make([]string, len(segments))
+	for i := range segments {
+		nodes[i] = strings.Join(segments[:i+1], ".")
+	}
+	return nodes
+}
+
+// SummarizeProjects counts open and completed tasks for every project node,
+// rolling each task up into all of its ancestor projects.
+func SummarizeProjects(taskList []Task) []ProjectSummary {
+	byName := make(map[string]*ProjectSummary)
+	for _, task := range taskList {
+		if task.Project == "" {
+			continue
+		}
+		for _, node := range projectAncestors(task.Project) {
+			summary, ok := byName[node]
+			if !ok {
+				summary = &ProjectSummary{Name: node}
+				byName[node] = summary
+			}
+			switch {
+			case task.Status == workflow.Completed():
+				summary.Completed++
+			case !task.IsTerminal():
+				summary.Open++
+			}
+		}
+	}
+
+	summaries := make([]ProjectSummary, 0, len(byName))
+	for _, summary := range byName {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
@@ -0,0 +1,89 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/wltechblog/notes/internal/index"
+	"github.com/wltechblog/notes/internal/query"
+)
+
+// QueryFields are the fields task queries can filter on.
+var QueryFields = query.Schema{
+	"id":        query.Keyword,
+	"name":      query.Text,
+	"content":   query.Text,
+	"status":    query.Keyword,
+	"tag":       query.List,
+	"tags":      query.List,
+	"project":   query.Path,
+	"priority":  query.Keyword,
+	"parent":    query.Keyword,
+	"note":      query.Keyword,
+	"recur":     query.Keyword,
+	"due":       query.Time,
+	"scheduled": query.Time,
+	"created":   query.Time,
+	"updated":   query.Time,
+	"urgency":   query.Number,
+}
+
+func ParseQuery(s string) (query.Node, error) {
+	return query.Parse(s, QueryFields, time.Now())
+}
+
+type taskRecord struct {
+	task *Task
+	now  time.Time
+}
+
+func (r taskRecord) Text() string {
+	return r.task.Name + "\n" + r.task.Content
+}
+
+func (r taskRecord) Field(name string) query.Value {
+	task := r.task
+	switch name {
+	case "id":
+		return query.Value{Str: task.ID}
+	case "name":
+		return query.Value{Str: task.Name}
+	case "content":
+		return query.Value{Str: task.Content}
+	case "status":
+		return query.Value{Str: string(task.Status)}
+	case "tag", "tags":
+		return query.Value{List: task.Tags}
+	case "project":
+		return query.Value{Str: task.Project}
+	case "priority":
+		return query.Value{Str: string(task.Priority)}
+	case "parent":
+		return query.Value{Str: task.ParentID}
+	case "note":
+		return query.Value{Str: task.NoteID}
+	case "recur":
+		return query.Value{Str: task.Recur}
+	case "due":
+		return query.Value{Time: task.DueAt}
+	case "scheduled":
+		return query.Value{Time: task.ScheduledAt}
+	case "created":
+		return query.Value{Time: &task.CreatedAt}
+	case "updated":
+		return query.Value{Time: &task.UpdatedAt}
+	case "urgency":
+		return query.Value{Num: task.Urgency(r.now)}
+	}
+	return query.Value{}
+}
+
+// indexedRecord is a taskRecord whose bare words are looked up in the
+// search index, so they match at the start of indexed words.
+type indexedRecord struct {
+	taskRecord
+	matcher *index.Matcher
+}
+
+func (r indexedRecord) MatchTerm(word string) (bool, bool) {
+	return r.matcher.Contains(r.task.ID, word)
+}
@@ -0,0 +1,240 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RecurKind string
+
+const (
+	RecurDaily   RecurKind = "daily"
+	RecurWeekly  RecurKind = "weekly"
+	RecurMonthly RecurKind = "monthly"
+	RecurEvery   RecurKind = "every"
+)
+
+// Recurrence is a rule for repeating a task. Daily, weekly and monthly rules
+// follow the calendar from the previous due date; every-N-days rules count
+// from the day the previous instance was completed.
+type Recurrence struct {
+	Kind     RecurKind
+	Weekdays []time.Weekday
+	Day      int
+	Days     int
+}
+
+var recurWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseRecurrence accepts "daily", "weekly" or "weekly:mon,thu", "monthly" or
+// "monthly:15", and "every:3d" (or "every:2w"). An empty string or "none"
+// yields nil.
+func ParseRecurrence(s string) (*Recurrence, error) {
+	rule := strings.ToLower(strings.TrimSpace(s))
+	if rule == "" || rule == "none" {
+		return nil, nil
+	}
+
+	kind, arg, _ := strings.Cut(rule, ":")
+	switch RecurKind(kind) {
+	case RecurDaily:
+		if arg == "" {
+			return &Recurrence{Kind: RecurDaily}, nil
+		}
+	case RecurWeekly:
+		r := &Recurrence{Kind: RecurWeekly}
+		if arg == "" {
+			return r, nil
+		}
+		for _, name := range strings.Split(arg, ",") {
+			name = strings.TrimSpace(name)
+			if len(name) > 3 {
+				name = name[:3]
+			}
+			weekday, ok := recurWeekdays[name]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday in recurrence: %s", s)
+			}
+			if !containsWeekday(r.Weekdays, weekday) {
+				r.Weekdays = append(r.Weekdays, weekday)
+			}
+		}
+		sort.Slice(r.Weekdays, func(i, j int) bool {
+			return weekdayIndex(r.Weekdays[i]) < weekdayIndex(r.Weekdays[j])
+		})
+		return r, nil
+	case RecurMonthly:
+		if arg == "" {
+			return &Recurrence{Kind: RecurMonthly}, nil
+		}
+		day, err := strconv.Atoi(arg)
+		if err == nil && day >= 1 && day <= 31 {
+			return &Recurrence{Kind: RecurMonthly, Day: day}, nil
+		}
+	case RecurEvery:
+		count, unit := arg, "d"
+		if strings.HasSuffix(arg, "d") || strings.HasSuffix(arg, "w") {
+			count, unit = arg[:len(arg)-1], arg[len(arg)-1:]
+		}
+		n, err := strconv.Atoi(count)
+		if err == nil && n > 0 {
+			if unit == "w" {
+				n *= 7
+			}
+			return &Recurrence{Kind: RecurEvery, Days: n}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid recurrence: %s (use daily, weekly[:mon,thu], monthly[:N] or every:Nd)", s)
+}
+
+func (r *Recurrence) String() string {
+	switch r.Kind {
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return string(RecurWeekly)
+		}
+		names := make([]string, len(r.Weekdays))
+		for i, weekday := range r.Weekdays {
+			names[i] = strings.ToLower(weekday.String()[:3])
+		}
+		return fmt.Sprintf("%s:%s", RecurWeekly, strings.Join(names, ","))
+	case RecurMonthly:
+		if r.Day == 0 {
+			return string(RecurMonthly)
+		}
+		return fmt.Sprintf("%s:%d", RecurMonthly, r.Day)
+	case RecurEvery:
+		return fmt.Sprintf("%s:%dd", RecurEvery, r.Days)
+	}
+	return string(r.Kind)
+}
+
+// Next returns the date of the instance following one anchored at prev and
+// completed at done. Calendar rules skip occurrences that are already past by
+// the time of completion, so finishing a weekly chore late still schedules
+// the next one in the future.
+func (r *Recurrence) Next(prev, done time.Time) time.Time {
+	if r.Kind == RecurEvery {
+		day := time.Date(done.Year(), done.Month(), done.Day(),
+			prev.Hour(), prev.Minute(), prev.Second(), 0, prev.Location())
+		return day.AddDate(0, 0, r.Days)
+	}
+
+	// A bare monthly rule keeps prev's day of the month. Each step clamps
+	// it afresh, so a series on the 31st returns to the 31st after February.
+	day := r.Day
+	if day == 0 {
+		day = prev.Day()
+	}
+	next := r.step(prev, day)
+	for !next.After(done) {
+		next = r.step(next, day)
+	}
+	return next
+}
+
+func (r *Recurrence) step(t time.Time, day int) time.Time {
+	switch r.Kind {
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return t.AddDate(0, 0, 7)
+		}
+		for days := 1; days <= 7; days++ {
+			next := t.AddDate(0, 0, days)
+			if containsWeekday(r.Weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	case RecurMonthly:
+		first := time.Date(t.Year(), t.Month()+1, 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return first.AddDate(0, 0, day-1)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// nextInstance builds the task that follows task in its series, or returns
+// nil if task does not recur.
+func nextInstance(task *Task, done time.Time) (*Task, error) {
+	rule, err := ParseRecurrence(task.Recur)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+
+	next := &Task{
+		Name:     task.Name,
+		Priority: task.Priority,
+		Estimate: task.Estimate,
+		Tags:     task.Tags,
+		Project:  task.Project,
+		ParentID: task.ParentID,
+		Recur:    task.Recur,
+		SeriesID: task.SeriesID,
+		Content:  task.Content,
+	}
+	if next.SeriesID == "" {
+		next.SeriesID = task.ID
+	}
+
+	anchor := done
+	if task.DueAt != nil {
+		anchor = *task.DueAt
+	} else if task.ScheduledAt != nil {
+		anchor = *task.ScheduledAt
+	}
+	// Pin a bare monthly rule to the series' day before the first instance
+	// is clamped into a shorter month and loses it.
+	if rule.Kind == RecurMonthly && rule.Day == 0 {
+		rule.Day = anchor.Day()
+		next.Recur = rule.String()
+	}
+	nextAnchor := rule.Next(anchor, done)
+	shift := nextAnchor.Sub(anchor)
+
+	if task.DueAt != nil || task.ScheduledAt == nil {
+		next.DueAt = &nextAnchor
+	}
+	if task.ScheduledAt != nil {
+		scheduled := task.ScheduledAt.Add(shift)
+		next.ScheduledAt = &scheduled
+	}
+
+	return next, nil
+}
+
+func (tm *TaskManager) SetRecurrence(id string, rule *Recurrence) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		task.Recur = ""
+		if rule != nil {
+			task.Recur = rule.String()
+			if task.SeriesID == "" {
+				task.SeriesID = task.ID
+			}
+		}
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, existing := range weekdays {
+		if existing == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdayIndex orders weekdays from Monday to Sunday.
+func weekdayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,109 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"daily", "daily"},
+		{" Weekly ", "weekly"},
+		{"weekly:thursday,mon,thu", "weekly:mon,thu"},
+		{"monthly", "monthly"},
+		{"monthly:31", "monthly:31"},
+		{"every:3", "every:3d"},
+		{"every:2w", "every:14d"},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.in)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q): %v", tt.in, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("ParseRecurrence(%q) = %s; want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "none"} {
+		if rule, err := ParseRecurrence(in); rule != nil || err != nil {
+			t.Errorf("ParseRecurrence(%q) = %v, %v; want nil, nil", in, rule, err)
+		}
+	}
+	for _, in := range []string{"hourly", "daily:2", "weekly:funday", "monthly:0", "monthly:32", "every:0d", "every:xd"} {
+		if _, err := ParseRecurrence(in); err == nil {
+			t.Errorf("ParseRecurrence(%q) succeeded; want an error", in)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		rule       string
+		prev, done time.Time
+		want       time.Time
+	}{
+		{"daily", date(2026, 10, 14), date(2026, 10, 14), date(2026, 10, 15)},
+		// Completing late skips occurrences already past.
+		{"daily", date(2026, 10, 14), date(2026, 10, 17), date(2026, 10, 18)},
+		{"weekly", date(2026, 10, 14), date(2026, 10, 14), date(2026, 10, 21)},
+		// 2026-10-14 is a Wednesday.
+		{"weekly:mon,thu", date(2026, 10, 14), date(2026, 10, 14), date(2026, 10, 15)},
+		{"weekly:mon,thu", date(2026, 10, 15), date(2026, 10, 15), date(2026, 10, 19)},
+		{"monthly", date(2026, 10, 14), date(2026, 10, 14), date(2026, 11, 14)},
+		{"monthly:15", date(2026, 10, 14), date(2026, 10, 14), date(2026, 11, 15)},
+		{"monthly", date(2027, 1, 31), date(2027, 1, 31), date(2027, 2, 28)},
+		{"monthly:31", date(2027, 2, 28), date(2027, 2, 28), date(2027, 3, 31)},
+		{"monthly:31", date(2027, 3, 31), date(2027, 3, 31), date(2027, 4, 30)},
+		{"monthly:29", date(2028, 1, 29), date(2028, 1, 29), date(2028, 2, 29)},
+		// A bare monthly rule keeps its day while skipping short months.
+		{"monthly", date(2027, 1, 31), date(2027, 3, 1), date(2027, 3, 31)},
+		// every:N counts from completion, keeping the time of day.
+		{"every:3d", date(2026, 10, 14), date(2026, 10, 20), date(2026, 10, 23)},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rule.Next(tt.prev, tt.done); !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s, %s) = %s; want %s", tt.rule,
+				tt.prev.Format("2006-01-02"), tt.done.Format("2006-01-02"), got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestNextInstanceMonthlyAnchor(t *testing.T) {
+	due := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	task := &Task{ID: "1", Name: "rent", Recur: "monthly", DueAt: &due}
+
+	var dues []string
+	for i := 0; i < 4; i++ {
+		next, err := nextInstance(task, *task.DueAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dues = append(dues, next.DueAt.Format("2006-01-02"))
+		if next.SeriesID != "1" {
+			t.Errorf("next instance SeriesID = %q; want 1", next.SeriesID)
+		}
+		task = next
+	}
+
+	want := []string{"2027-02-28", "2027-03-31", "2027-04-30", "2027-05-31"}
+	for i := range want {
+		if dues[i] != want[i] {
+			t.Fatalf("due dates = %v; want %v", dues, want)
+		}
+	}
+	if task.Recur != "monthly:31" {
+		t.Errorf("Recur = %q; want the rule pinned to monthly:31", task.Recur)
+	}
+}
@@ -0,0 +1,215 @@
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/fuzzy"
+	"github.com/wltechblog/notes/internal/index"
+	"github.com/wltechblog/notes/internal/query"
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// Result is a task matched by SearchTasks with its relevance score.
+type Result struct {
+	Task
+	Score float64 `json:"score"`
+}
+
+// SearchTasks returns the tasks matching filter and the query q, best match
+// first. Plain words are looked up in the search index, which is built on
+// first use, and match the start of words in the name or content; quoted
+// phrases and field predicates are checked against each task (see package
+// query). Results are ranked with BM25.
+func (tm *TaskManager) SearchTasks(q string, filter Filter) ([]Result, error) {
+	node, err := ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Query != nil {
+		node = query.And(filter.Query, node)
+	}
+	filter.Query = node
+
+	idx, err := tm.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+	filter.matcher = idx.Matcher()
+
+	// Tasks lacking a required word cannot match, so only the index's
+	// candidates are read when the query has one, unless blockers have to
+	// be worked out from every task.
+	var ids []string
+	required := query.Required(node)
+	if len(required) > 0 && !filter.Ready && !filter.Blocked {
+		for id := range idx.Candidates(strings.Join(required, " ")) {
+			ids = append(ids, id)
+		}
+	} else if ids, err = tm.store.List(); err != nil {
+		return nil, fmt.Errorf("failed to read tasks directory: %w", err)
+	}
+
+	var all []Task
+	for _, id := range ids {
+		task, err := tm.loadTask(id)
+		if err != nil {
+			continue
+		}
+		all = append(all, task)
+	}
+
+	scores := idx.Score(strings.Join(query.Terms(node), " "))
+	var results []Result
+	for _, task := range filterTasks(all, filter, time.Now()) {
+		results = append(results, Result{Task: task, Score: scores[task.ID]})
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// SearchWords returns a function reporting whether a word of a task is
+// one that a plain word of the query q finds in the search index, to
+// highlight what SearchTasks matched.
+func (tm *TaskManager) SearchWords(q string) (func(word string) bool, error) {
+	node, err := ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	stem, err := index.Stemmed(tm.store.Sub(index.Dir))
+	if err != nil {
+		return nil, err
+	}
+	return index.WordMatcher(strings.Join(query.Terms(node), " "), stem), nil
+}
+
+// Scope selects the parts of a task SearchTasksRegex matches.
+type Scope string
+
+const (
+	ScopeName    Scope = "name"
+	ScopeContent Scope = "content"
+	ScopeBoth    Scope = "both"
+)
+
+// SearchTasksRegex returns the tasks matching filter whose name or content,
+// as scope selects, match re. Tasks with the most matches come first.
+func (tm *TaskManager) SearchTasksRegex(re *regexp.Regexp, scope Scope, filter Filter) ([]Result, error) {
+	return tm.rank(filter, func(task *Task) float64 {
+		var count int
+		if scope != ScopeContent {
+			count += len(re.FindAllStringIndex(task.Name, -1))
+		}
+		if scope != ScopeName {
+			count += len(re.FindAllStringIndex(task.Content, -1))
+		}
+		return float64(count)
+	})
+}
+
+// SearchTasksFuzzy returns the tasks matching filter that contain every
+// word of q, allowing for a few typos in each (see package fuzzy). Closer
+// matches come first, scored from 0 to 1.
+func (tm *TaskManager) SearchTasksFuzzy(q string, filter Filter) ([]Result, error) {
+	return tm.rank(filter, func(task *Task) float64 {
+		score, _ := fuzzy.Score(q, taskRecord{task: task}.Text())
+		return score
+	})
+}
+
+// rank scores the tasks matching filter, leaving out those scoring 0, and
+// orders them best first.
+func (tm *TaskManager) rank(filter Filter, score func(*Task) float64) ([]Result, error) {
+	taskList, err := tm.ListTasks(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, task := range taskList {
+		if s := score(&task); s > 0 {
+			results = append(results, Result{Task: task, Score: s})
+		}
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// sortResults orders results by descending score, then by ID.
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return storage.LessID(results[i].ID, results[j].ID)
+	})
+}
+
+// Reindex rebuilds the search index from every task and returns how many
+// were indexed. With stem set, words are indexed by their stems.
+func (tm *TaskManager) Reindex(stem bool) (int, error) {
+	var count int
+	err := tm.store.Sub(index.Dir).Update(func(store storage.Store) error {
+		idx, err := index.Open(store)
+		if err != nil {
+			return err
+		}
+		count, err = tm.rebuildIndex(idx, stem)
+		return err
+	})
+	return count, err
+}
+
+func (tm *TaskManager) rebuildIndex(idx *index.Index, stem bool) (int, error) {
+	taskList, err := tm.ListTasks(Filter{})
+	if err != nil {
+		return 0, err
+	}
+
+	docs := make(map[string]string, len(taskList))
+	for _, task := range taskList {
+		docs[task.ID] = taskRecord{task: &task}.Text()
+	}
+	idx.Rebuild(docs, stem)
+
+	if err := idx.Save(); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// searchIndex opens the search index, building it under the index lock if
+// it does not exist yet.
+func (tm *TaskManager) searchIndex() (*index.Index, error) {
+	store := tm.store.Sub(index.Dir)
+	idx, err := index.Open(store)
+	if err != nil || idx.Exists() {
+		return idx, err
+	}
+
+	err = store.Update(func(store storage.Store) error {
+		var err error
+		if idx, err = index.Open(store); err != nil || idx.Exists() {
+			return err
+		}
+		_, err = tm.rebuildIndex(idx, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// indexTask brings task's entry in the search index up to date. Until the
+// first search builds the index there is nothing to update.
+func (tm *TaskManager) indexTask(task *Task) error {
+	return index.Put(tm.store.Sub(index.Dir), task.ID, taskRecord{task: task}.Text())
+}
+
+func (tm *TaskManager) unindexTask(id string) error {
+	return index.Delete(tm.store.Sub(index.Dir), id)
+}
@@ -1,16 +1,24 @@
 package tasks
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/wltechblog/notes/internal/index"
 	"github.com/wltechblog/notes/internal/platform"
+	"github.com/wltechblog/notes/internal/query"
+	"github.com/wltechblog/notes/internal/record"
+	"github.com/wltechblog/notes/internal/storage"
+	"github.com/wltechblog/notes/internal/tags"
+	"github.com/wltechblog/notes/internal/views"
 )
 
 type Status string
@@ -22,17 +30,91 @@ const (
 )
 
 type Task struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Status    Status    `json:"status"`
-	NoteID    string    `json:"note_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Content   string    `json:"content"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Status      Status        `json:"status"`
+	NoteID      string        `json:"note_id"`
+	NoteLine    int           `json:"note_line,omitempty"`
+	Priority    Priority      `json:"priority,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Project     string        `json:"project,omitempty"`
+	ParentID    string        `json:"parent_id,omitempty"`
+	DependsOn   []string      `json:"depends_on,omitempty"`
+	Recur       string        `json:"recur,omitempty"`
+	SeriesID    string        `json:"series_id,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	DueAt       *time.Time    `json:"due_at,omitempty"`
+	ScheduledAt *time.Time    `json:"scheduled_at,omitempty"`
+	Estimate    time.Duration `json:"estimate,omitempty"`
+	StartedAt   *time.Time    `json:"started_at,omitempty"`
+	TimeLog     []TimeEntry   `json:"time_log,omitempty"`
+	Transitions []Transition  `json:"transitions,omitempty"`
+	Content     string        `json:"content"`
 }
 
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.IsTerminal() && t.DueAt != nil && t.DueAt.Before(now)
+}
+
+type Filter struct {
+	Status    Status
+	Overdue   bool
+	DueAfter  time.Time
+	DueBefore time.Time
+	Tags      []string
+	NotTags   []string
+	Project   string
+	Ready     bool
+	Blocked   bool
+	NoteID    string
+	Query     query.Node
+
+	// matcher, when set, answers bare words in Query from the search index.
+	matcher *index.Matcher
+}
+
+func (f Filter) Match(task *Task, now time.Time) bool {
+	if f.Status != "" && task.Status != f.Status {
+		return false
+	}
+	if f.Overdue && !task.IsOverdue(now) {
+		return false
+	}
+	if !f.DueAfter.IsZero() && (task.DueAt == nil || task.DueAt.Before(f.DueAfter)) {
+		return false
+	}
+	if !f.DueBefore.IsZero() && (task.DueAt == nil || !task.DueAt.Before(f.DueBefore)) {
+		return false
+	}
+	if !tags.Match(task.Tags, f.Tags, f.NotTags) {
+		return false
+	}
+	if f.Project != "" && !InProject(task.Project, f.Project) {
+		return false
+	}
+	if f.NoteID != "" && task.NoteID != f.NoteID {
+		return false
+	}
+	var r query.Record = taskRecord{task: task, now: now}
+	if f.matcher != nil {
+		r = indexedRecord{taskRecord{task: task, now: now}, f.matcher}
+	}
+	if !query.Match(f.Query, r) {
+		return false
+	}
+	return true
+}
+
+type Revision struct {
+	Number int  `json:"number"`
+	Task   Task `json:"task"`
+}
+
+const historyDir = ".history"
+
 type TaskManager struct {
-	baseDir string
+	store storage.Store
 }
 
 func NewTaskManager() (*TaskManager, error) {
@@ -41,66 +123,125 @@ func NewTaskManager() (*TaskManager, error) {
 		return nil, err
 	}
 
-	if err := os.MkdirAll(baseDir, platform.GetDataDirPerm()); err != nil {
+	store, err := storage.NewFileStore(baseDir)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create tasks directory: %w", err)
 	}
 
-	return &TaskManager{baseDir: baseDir}, nil
+	return NewTaskManagerWithStore(store), nil
 }
 
-func (tm *TaskManager) ListTasks(statusFilter Status) ([]Task, error) {
-	var tasks []Task
+func NewTaskManagerWithStore(store storage.Store) *TaskManager {
+	return &TaskManager{store: store}
+}
 
-	entries, err := os.ReadDir(tm.baseDir)
+// Views returns the saved task views.
+func (tm *TaskManager) Views() *views.Views {
+	return views.New(tm.store.Sub(views.Dir))
+}
+
+func (tm *TaskManager) ListTasks(filter Filter) ([]Task, error) {
+	var all []Task
+	now := time.Now()
+
+	ids, err := tm.store.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read tasks directory: %w", err)
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".txt") {
-			continue
-		}
-
-		id := strings.TrimSuffix(entry.Name(), ".txt")
+	for _, id := range ids {
 		task, err := tm.loadTask(id)
 		if err != nil {
 			continue
 		}
+		all = append(all, task)
+	}
 
-		if statusFilter == "" || task.Status == statusFilter {
-			tasks = append(tasks, task)
+	return filterTasks(all, filter, now), nil
+}
+
+// filterTasks returns the tasks of all that match filter. all must include
+// every task when filter selects ready or blocked tasks, so that blockers
+// are known.
+func filterTasks(all []Task, filter Filter, now time.Time) []Task {
+	var blockers map[string][]string
+	if filter.Ready || filter.Blocked {
+		blockers = blockerIndex(all)
+	}
+
+	var tasks []Task
+	for _, task := range all {
+		if !filter.Match(&task, now) {
+			continue
+		}
+		if filter.Ready && (task.IsTerminal() || len(blockers[task.ID]) > 0) {
+			continue
+		}
+		if filter.Blocked && len(blockers[task.ID]) == 0 {
+			continue
 		}
+		tasks = append(tasks, task)
 	}
 
-	return tasks, nil
+	return tasks
 }
 
 func (tm *TaskManager) CreateTask(name string, content string) (*Task, error) {
-	if name == "" {
-		name = strconv.FormatInt(time.Now().Unix(), 10)
+	task := &Task{
+		Name:    name,
+		Content: content,
 	}
 
-	timestamp := time.Now()
-	id, err := tm.getNextID()
-	if err != nil {
+	if err := tm.Create(task); err != nil {
 		return nil, err
 	}
 
-	task := &Task{
-		ID:        id,
-		Name:      name,
-		Status:    StatusOpen,
-		NoteID:    "",
-		CreatedAt: timestamp,
-		UpdatedAt: timestamp,
-		Content:   content,
+	return task, nil
+}
+
+// Create assigns task a new ID and creation timestamps and saves it. Fields
+// such as due dates may be set beforehand; an empty name defaults to the
+// current Unix time and an empty status to the workflow's initial status. A
+// recurring task without a series starts a new one.
+func (tm *TaskManager) Create(task *Task) error {
+	if err := ValidateProject(task.Project); err != nil {
+		return err
+	}
+	if task.Recur != "" {
+		rule, err := ParseRecurrence(task.Recur)
+		if err != nil {
+			return err
+		}
+		task.Recur = rule.String()
+	}
+	if task.ParentID != "" {
+		if _, err := tm.loadTask(task.ParentID); err != nil {
+			return fmt.Errorf("parent task not found: %s", task.ParentID)
+		}
+	}
+	if task.Name == "" {
+		task.Name = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	if task.Status == "" {
+		task.Status = workflow.Initial()
+	} else if !workflow.Valid(task.Status) {
+		return fmt.Errorf("invalid status: %s (must be one of: %s)", task.Status, workflow.Describe())
 	}
 
-	if err := tm.saveTask(task); err != nil {
-		return nil, err
+	timestamp := time.Now()
+	id, err := tm.store.NextID()
+	if err != nil {
+		return err
 	}
 
-	return task, nil
+	task.ID = id
+	if task.Recur != "" && task.SeriesID == "" {
+		task.SeriesID = id
+	}
+	task.CreatedAt = timestamp
+	task.UpdatedAt = timestamp
+
+	return tm.saveTask(task)
 }
 
 func (tm *TaskManager) GetTask(id string) (*Task, error) {
@@ -112,132 +253,388 @@ func (tm *TaskManager) GetTask(id string) (*Task, error) {
 }
 
 func (tm *TaskManager) UpdateTask(id string, content string) (*Task, error) {
-	task, err := tm.loadTask(id)
-	if err != nil {
-		return nil, err
-	}
+	return tm.modify(id, func(task *Task) error {
+		task.Content = content
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
 
-	task.Content = content
-	task.UpdatedAt = time.Now()
+func (tm *TaskManager) SetPriority(id string, priority Priority) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		task.Priority = priority
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
 
-	if err := tm.saveTask(&task); err != nil {
-		return nil, err
-	}
+func (tm *TaskManager) AddTags(id string, tagList ...string) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		for _, tag := range tagList {
+			task.Tags = tags.Add(task.Tags, tag)
+		}
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
 
-	return &task, nil
+func (tm *TaskManager) RemoveTags(id string, tagList ...string) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		for _, tag := range tagList {
+			task.Tags = tags.Remove(task.Tags, tag)
+		}
+		task.UpdatedAt = time.Now()
+		return nil
+	})
 }
 
-func (tm *TaskManager) UpdateTaskStatus(id string, status Status) (*Task, error) {
-	task, err := tm.loadTask(id)
+func (tm *TaskManager) TagCounts() (map[string]int, error) {
+	taskList, err := tm.ListTasks(Filter{})
 	if err != nil {
 		return nil, err
 	}
 
-	task.Status = status
-	task.UpdatedAt = time.Now()
+	counts := make(map[string]int)
+	for _, task := range taskList {
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+	return counts, nil
+}
 
-	if err := tm.saveTask(&task); err != nil {
+func (tm *TaskManager) SetProject(id string, project string) (*Task, error) {
+	if err := ValidateProject(project); err != nil {
 		return nil, err
 	}
 
-	return &task, nil
+	return tm.modify(id, func(task *Task) error {
+		task.Project = project
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// SetNote links a task to a note, or unlinks it when noteID is empty, and
+// records the line of the note's checklist item the task came from (0 for
+// none). The caller is responsible for checking that the note exists.
+func (tm *TaskManager) SetNote(id string, noteID string, line int) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		task.NoteID = noteID
+		task.NoteLine = line
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (tm *TaskManager) SetDue(id string, due *time.Time) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		task.DueAt = due
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (tm *TaskManager) SetScheduled(id string, scheduled *time.Time) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		task.ScheduledAt = scheduled
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+type StatusUpdate struct {
+	Task *Task
+	// Unblocked lists tasks whose last open dependency was this task.
+	Unblocked []Task
+	// Next is the new instance created when a recurring task is completed.
+	Next *Task
 }
 
-func (tm *TaskManager) SearchTasks(keyword string) ([]Task, error) {
-	tasks, err := tm.ListTasks("")
+// UpdateTaskStatus moves a task to status, recording the transition and an
+// optional comment in the task's status history.
+func (tm *TaskManager) UpdateTaskStatus(id string, status Status, comment string) (*StatusUpdate, error) {
+	if !workflow.Valid(status) {
+		return nil, fmt.Errorf("invalid status: %s (must be one of: %s)", status, workflow.Describe())
+	}
+
+	var (
+		task    Task
+		wasOpen bool
+		update  = &StatusUpdate{Task: &task}
+	)
+	err := tm.update(func(tx *TaskManager) error {
+		var err error
+		if task, err = tx.loadTask(id); err != nil {
+			return err
+		}
+		if !workflow.CanTransition(task.Status, status) {
+			return fmt.Errorf("cannot move task %s from %s to %s (allowed: %s)",
+				id, task.Status, status, joinStatuses(workflow.Targets(task.Status)))
+		}
+
+		wasOpen = !task.IsTerminal()
+		task.UpdatedAt = time.Now()
+		if status != task.Status || comment != "" {
+			task.Transitions = append(task.Transitions, Transition{
+				At:      task.UpdatedAt,
+				From:    task.Status,
+				To:      status,
+				Comment: cleanComment(comment),
+			})
+		}
+		task.Status = status
+		if task.StartedAt != nil && task.IsTerminal() {
+			stopTimer(&task, task.UpdatedAt)
+		}
+
+		if err := tx.saveTask(&task); err != nil {
+			return err
+		}
+
+		if wasOpen && status == workflow.Completed() {
+			next, err := nextInstance(&task, task.UpdatedAt)
+			if err != nil {
+				return err
+			}
+			if next != nil {
+				if err := tx.Create(next); err != nil {
+					return fmt.Errorf("failed to create next instance: %w", err)
+				}
+				update.Next = next
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var matchingTasks []Task
-	for _, task := range tasks {
-		if strings.Contains(strings.ToLower(task.Content), strings.ToLower(keyword)) ||
-			strings.Contains(strings.ToLower(task.Name), strings.ToLower(keyword)) {
-			matchingTasks = append(matchingTasks, task)
+	if wasOpen && task.IsTerminal() {
+		taskList, err := tm.ListTasks(Filter{})
+		if err != nil {
+			return nil, err
+		}
+		blockers := blockerIndex(taskList)
+		for _, other := range taskList {
+			if !other.IsTerminal() && containsID(other.DependsOn, id) && len(blockers[other.ID]) == 0 {
+				update.Unblocked = append(update.Unblocked, other)
+			}
 		}
 	}
 
-	return matchingTasks, nil
+	return update, nil
 }
 
 func (tm *TaskManager) loadTask(id string) (Task, error) {
-	taskPath := filepath.Join(tm.baseDir, id+".txt")
-	data, err := os.ReadFile(taskPath)
+	data, err := tm.store.Get(id)
 	if err != nil {
 		return Task{}, fmt.Errorf("failed to read task: %w", err)
 	}
 
-	lines := strings.Split(string(data), "\n")
-	if len(lines) < 5 {
-		return Task{}, fmt.Errorf("invalid task format")
+	return parseTask(id, data)
+}
+
+func parseTask(id string, data []byte) (Task, error) {
+	fields, content, err := record.Parse(data)
+	if err != nil {
+		return Task{}, fmt.Errorf("invalid task format: %w", err)
 	}
 
-	createdAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[0], "Created: "))
+	createdAt, err := time.Parse(time.RFC3339, record.Get(fields, "Created"))
 	if err != nil {
 		return Task{}, fmt.Errorf("failed to parse created timestamp: %w", err)
 	}
 
-	updatedAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[1], "Updated: "))
+	updatedAt, err := time.Parse(time.RFC3339, record.Get(fields, "Updated"))
 	if err != nil {
 		return Task{}, fmt.Errorf("failed to parse updated timestamp: %w", err)
 	}
 
-	status := Status(strings.TrimPrefix(lines[2], "Status: "))
-	noteID := strings.TrimPrefix(lines[3], "NoteID: ")
-	name := strings.TrimPrefix(lines[4], "Name: ")
-	content := strings.Join(lines[5:], "\n")
+	dueAt, err := parseOptionalTime(record.Get(fields, "Due"))
+	if err != nil {
+		return Task{}, fmt.Errorf("failed to parse due date: %w", err)
+	}
+
+	scheduledAt, err := parseOptionalTime(record.Get(fields, "Scheduled"))
+	if err != nil {
+		return Task{}, fmt.Errorf("failed to parse scheduled date: %w", err)
+	}
+
+	var noteLine int
+	if value := record.Get(fields, "NoteLine"); value != "" {
+		if noteLine, err = strconv.Atoi(value); err != nil {
+			return Task{}, fmt.Errorf("failed to parse note line: %w", err)
+		}
+	}
+
+	var estimate time.Duration
+	if value := record.Get(fields, "Estimate"); value != "" {
+		if estimate, err = time.ParseDuration(value); err != nil {
+			return Task{}, fmt.Errorf("failed to parse estimate: %w", err)
+		}
+	}
+
+	startedAt, err := parseOptionalTime(record.Get(fields, "Started"))
+	if err != nil {
+		return Task{}, fmt.Errorf("failed to parse timer start: %w", err)
+	}
+
+	var timeLog []TimeEntry
+	for _, value := range record.All(fields, "Time") {
+		entry, err := parseTimeEntry(value)
+		if err != nil {
+			return Task{}, err
+		}
+		timeLog = append(timeLog, entry)
+	}
+
+	var transitions []Transition
+	for _, value := range record.All(fields, "Transition") {
+		tr, err := parseTransition(value)
+		if err != nil {
+			return Task{}, err
+		}
+		transitions = append(transitions, tr)
+	}
 
 	return Task{
-		ID:        id,
-		Name:      name,
-		Status:    status,
-		NoteID:    noteID,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-		Content:   content,
+		ID:          id,
+		Name:        record.Get(fields, "Name"),
+		Status:      Status(record.Get(fields, "Status")),
+		NoteID:      record.Get(fields, "NoteID"),
+		NoteLine:    noteLine,
+		Priority:    Priority(record.Get(fields, "Priority")),
+		Tags:        tags.Parse(record.Get(fields, "Tags")),
+		Project:     record.Get(fields, "Project"),
+		ParentID:    record.Get(fields, "Parent"),
+		DependsOn:   parseIDList(record.Get(fields, "Depends")),
+		Recur:       record.Get(fields, "Recur"),
+		SeriesID:    record.Get(fields, "Series"),
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+		DueAt:       dueAt,
+		ScheduledAt: scheduledAt,
+		Estimate:    estimate,
+		StartedAt:   startedAt,
+		TimeLog:     timeLog,
+		Transitions: transitions,
+		Content:     content,
 	}, nil
 }
 
-func (tm *TaskManager) saveTask(task *Task) error {
+func parseIDList(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func formatTask(task *Task) []byte {
 	var content string
 	content += fmt.Sprintf("Created: %s\n", task.CreatedAt.Format(time.RFC3339))
 	content += fmt.Sprintf("Updated: %s\n", task.UpdatedAt.Format(time.RFC3339))
 	content += fmt.Sprintf("Status: %s\n", task.Status)
 	content += fmt.Sprintf("NoteID: %s\n", task.NoteID)
+	if task.NoteLine > 0 {
+		content += fmt.Sprintf("NoteLine: %d\n", task.NoteLine)
+	}
+	if task.Priority != PriorityNone {
+		content += fmt.Sprintf("Priority: %s\n", task.Priority)
+	}
+	if len(task.Tags) > 0 {
+		content += fmt.Sprintf("Tags: %s\n", tags.Format(task.Tags))
+	}
+	if task.Project != "" {
+		content += fmt.Sprintf("Project: %s\n", task.Project)
+	}
+	if task.ParentID != "" {
+		content += fmt.Sprintf("Parent: %s\n", task.ParentID)
+	}
+	if len(task.DependsOn) > 0 {
+		content += fmt.Sprintf("Depends: %s\n", strings.Join(task.DependsOn, ","))
+	}
+	if task.Recur != "" {
+		content += fmt.Sprintf("Recur: %s\n", task.Recur)
+	}
+	if task.SeriesID != "" {
+		content += fmt.Sprintf("Series: %s\n", task.SeriesID)
+	}
+	if task.DueAt != nil {
+		content += fmt.Sprintf("Due: %s\n", task.DueAt.Format(time.RFC3339))
+	}
+	if task.ScheduledAt != nil {
+		content += fmt.Sprintf("Scheduled: %s\n", task.ScheduledAt.Format(time.RFC3339))
+	}
+	if task.Estimate > 0 {
+		content += fmt.Sprintf("Estimate: %s\n", task.Estimate)
+	}
+	if task.StartedAt != nil {
+		content += fmt.Sprintf("Started: %s\n", task.StartedAt.Format(time.RFC3339))
+	}
+	for _, entry := range task.TimeLog {
+		content += fmt.Sprintf("Time: %s %s\n", entry.Start.Format(time.RFC3339), entry.Duration)
+	}
+	for _, tr := range task.Transitions {
+		content += fmt.Sprintf("Transition: %s\n", formatTransition(tr))
+	}
 	content += fmt.Sprintf("Name: %s\n", task.Name)
 	content += task.Content
+	return []byte(content)
+}
 
-	taskPath := filepath.Join(tm.baseDir, task.ID+".txt")
-	if err := os.WriteFile(taskPath, []byte(content), platform.GetDataFilePerm()); err != nil {
+func (tm *TaskManager) saveTask(task *Task) error {
+	if err := tm.archiveRevision(task); err != nil {
+		return err
+	}
+
+	if err := tm.store.Put(task.ID, formatTask(task)); err != nil {
 		return fmt.Errorf("failed to save task: %w", err)
 	}
 
-	return nil
+	return tm.indexTask(task)
 }
 
-func (tm *TaskManager) getNextID() (string, error) {
-	counterFile := filepath.Join(tm.baseDir, ".counter")
+// update runs fn with a manager whose store already holds the write lock,
+// so a load-modify-save through it cannot interleave with another writer.
+func (tm *TaskManager) update(fn func(tx *TaskManager) error) error {
+	return tm.store.Update(func(store storage.Store) error {
+		return fn(&TaskManager{store: store})
+	})
+}
 
-	data, err := os.ReadFile(counterFile)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return "", fmt.Errorf("failed to read counter file: %w", err)
+// modify loads a task, applies change and saves the result under the store
+// lock.
+func (tm *TaskManager) modify(id string, change func(task *Task) error) (*Task, error) {
+	var task Task
+	err := tm.update(func(tx *TaskManager) error {
+		var err error
+		if task, err = tx.loadTask(id); err != nil {
+			return err
 		}
-		data = []byte("0")
-	}
-
-	currentID, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err := change(&task); err != nil {
+			return err
+		}
+		return tx.saveTask(&task)
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to parse counter: %w", err)
-	}
-
-	nextID := currentID + 1
-
-	if err := os.WriteFile(counterFile, []byte(strconv.FormatInt(nextID, 10)), platform.GetDataFilePerm()); err != nil {
-		return "", fmt.Errorf("failed to write counter: %w", err)
+		return nil, err
 	}
-
-	return strconv.FormatInt(nextID, 10), nil
+	return &task, nil
 }
 
 func (tm *TaskManager) EditInEditor(task *Task) error {
@@ -285,27 +682,162 @@ func (tm *TaskManager) EditInEditor(task *Task) error {
 	return err
 }
 
-func (tm *TaskManager) DeleteTask(id string) error {
-	task, err := tm.GetTask(id)
+// DeleteTask moves a task to the trash. noteTrashed records that the caller
+// moved the task's linked note to the trash as well, so that restoring the
+// task can bring the note back too.
+func (tm *TaskManager) DeleteTask(id string, noteTrashed bool) error {
+	return tm.update(func(tx *TaskManager) error {
+		data, err := tx.store.Get(id)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+
+		if err := tx.trashRecord(id, data, noteTrashed); err != nil {
+			return err
+		}
+
+		if err := tx.store.Delete(id); err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+
+		return tx.unindexTask(id)
+	})
+}
+
+func (tm *TaskManager) PurgeTask(id string) error {
+	if err := tm.store.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	if err := tm.history(id).Clear(); err != nil {
+		return fmt.Errorf("failed to delete task history: %w", err)
+	}
+	return tm.unindexTask(id)
+}
+
+func (tm *TaskManager) history(id string) storage.Store {
+	return tm.store.Sub(historyDir).Sub(id)
+}
+
+// archiveRevision keeps the stored version of task in its history before it
+// is overwritten. Saves that change nothing but the timestamp, and the empty
+// placeholder written by CreateTask before the first edit, are not kept.
+func (tm *TaskManager) archiveRevision(task *Task) error {
+	prevData, err := tm.store.Get(task.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to read task: %w", err)
 	}
 
-	if task.NoteID != "" {
-		notesDir, err := platform.GetDataDir(platform.NotesSubdir)
-		if err != nil {
-			return fmt.Errorf("failed to get notes directory: %w", err)
+	if prev, err := parseTask(task.ID, prevData); err == nil {
+		if prev.Content == "" && prev.CreatedAt.Equal(prev.UpdatedAt) {
+			return nil
 		}
-		notePath := filepath.Join(notesDir, task.NoteID+".txt")
-		if err := os.Remove(notePath); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("failed to delete associated note: %w", err)
+		unchanged := *task
+		unchanged.UpdatedAt = prev.UpdatedAt
+		if bytes.Equal(formatTask(&unchanged), prevData) {
+			return nil
 		}
 	}
 
-	taskPath := filepath.Join(tm.baseDir, id+".txt")
-	if err := os.Remove(taskPath); err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+	history := tm.history(task.ID)
+	rev, err := history.NextID()
+	if err != nil {
+		return fmt.Errorf("failed to allocate revision: %w", err)
+	}
+	if err := history.Put(rev, prevData); err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
 	}
 
 	return nil
 }
+
+func (tm *TaskManager) History(id string) ([]Revision, error) {
+	if _, err := tm.loadTask(id); err != nil {
+		return nil, err
+	}
+
+	revs, err := tm.history(id).List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task history: %w", err)
+	}
+
+	var revisions []Revision
+	for _, rev := range revs {
+		number, err := strconv.Atoi(rev)
+		if err != nil {
+			continue
+		}
+		task, err := tm.GetRevision(id, number)
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, Revision{Number: number, Task: *task})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+
+	return revisions, nil
+}
+
+func (tm *TaskManager) GetRevision(id string, rev int) (*Task, error) {
+	data, err := tm.history(id).Get(strconv.Itoa(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision %d of task %s: %w", rev, id, err)
+	}
+
+	task, err := parseTask(id, data)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RestoreRevision brings back a task's name, content and descriptive fields
+// from revision rev. Its history of work is kept as it is now: time already
+// logged, a running timer and the estimate are not rolled back. Nor are its
+// dependencies and parent, which were checked for cycles against the tasks
+// as they are now. A different status in the revision must be one the
+// workflow lets the task move to, as for UpdateTaskStatus.
+func (tm *TaskManager) RestoreRevision(id string, rev int) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		old, err := tm.GetRevision(id, rev)
+		if err != nil {
+			return err
+		}
+
+		current := *task
+		*task = *old
+		task.CreatedAt = current.CreatedAt
+		task.UpdatedAt = time.Now()
+		task.Transitions = current.Transitions
+		task.TimeLog = current.TimeLog
+		task.StartedAt = current.StartedAt
+		task.Estimate = current.Estimate
+		task.DependsOn = current.DependsOn
+		task.ParentID = current.ParentID
+		if task.Status != current.Status {
+			if !workflow.Valid(task.Status) {
+				return fmt.Errorf("cannot restore revision %d: status %s is no longer in the workflow (must be one of: %s)",
+					rev, task.Status, workflow.Describe())
+			}
+			if !workflow.CanTransition(current.Status, task.Status) {
+				return fmt.Errorf("cannot restore revision %d: task %s cannot move from %s to %s (allowed: %s)",
+					rev, id, current.Status, task.Status, joinStatuses(workflow.Targets(current.Status)))
+			}
+			if task.StartedAt != nil && task.IsTerminal() {
+				stopTimer(task, task.UpdatedAt)
+			}
+			task.Transitions = append(task.Transitions, Transition{
+				At:      task.UpdatedAt,
+				From:    current.Status,
+				To:      task.Status,
+				Comment: fmt.Sprintf("restored revision %d", rev),
+			})
+		}
+		return nil
+	})
+}
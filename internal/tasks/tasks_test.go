@@ -0,0 +1,179 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wltechblog/notes/internal/config"
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+func TestRestoreRevisionKeepsTrackedTime(t *testing.T) {
+	tm := NewTaskManagerWithStore(storage.NewMemoryStore())
+	task, err := tm.CreateTask("write report", "first draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.UpdateTask(task.ID, "second draft"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.LogTime(task.ID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.SetEstimate(task.ID, 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.Start(task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := tm.RestoreRevision(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Content != "first draft" {
+		t.Errorf("Content = %q; want the revision's %q", restored.Content, "first draft")
+	}
+	if len(restored.TimeLog) != 1 || restored.TimeLog[0].Duration != time.Hour {
+		t.Errorf("TimeLog = %v; want the hour logged after the revision", restored.TimeLog)
+	}
+	if restored.Estimate != 2*time.Hour {
+		t.Errorf("Estimate = %v; want 2h", restored.Estimate)
+	}
+	if restored.StartedAt == nil {
+		t.Error("running timer was stopped by the restore")
+	}
+}
+
+func TestRestoreRevisionKeepsLinks(t *testing.T) {
+	tm := NewTaskManagerWithStore(storage.NewMemoryStore())
+	var ids []string
+	for _, name := range []string{"a", "b"} {
+		task, err := tm.CreateTask(name, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.ID)
+	}
+	a, b := ids[0], ids[1]
+
+	// Revision 1 of a depends on b and sits under it; then the links are
+	// reversed, which an old revision must not undo into a cycle.
+	if _, err := tm.AddDependencies(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.SetParent(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.RemoveDependencies(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.SetParent(a, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.AddDependencies(b, a); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.SetParent(b, a); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := tm.History(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var linked int
+	for _, rev := range revs {
+		if len(rev.Task.DependsOn) > 0 && rev.Task.ParentID != "" {
+			linked = rev.Number
+		}
+	}
+	if linked == 0 {
+		t.Fatal("no revision of a with both links")
+	}
+
+	restored, err := tm.RestoreRevision(a, linked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.DependsOn) != 0 || restored.ParentID != "" {
+		t.Errorf("restore brought back DependsOn %v and ParentID %q; want none", restored.DependsOn, restored.ParentID)
+	}
+}
+
+func TestRestoreRevisionChecksStatus(t *testing.T) {
+	w, err := NewWorkflow(&config.Workflow{
+		Statuses: []config.Status{{Name: "open"}, {Name: "review"}, {Name: "done", Terminal: true}},
+		Transitions: map[string][]string{
+			"open":   {"review"},
+			"review": {"done"},
+			"done":   {"review"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer UseWorkflow(CurrentWorkflow())
+	UseWorkflow(w)
+
+	tm := NewTaskManagerWithStore(storage.NewMemoryStore())
+	task, err := tm.CreateTask("ship it", "draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range []Status{"review", "done"} {
+		if _, err := tm.UpdateTaskStatus(task.ID, status, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Revision 1 is open, and done may not move back to open.
+	_, err = tm.RestoreRevision(task.ID, 1)
+	if err == nil || !strings.Contains(err.Error(), "cannot move from done to open") {
+		t.Fatalf("RestoreRevision error = %v; want a rejected transition", err)
+	}
+	if current, _ := tm.GetTask(task.ID); current.Status != "done" {
+		t.Errorf("Status after rejected restore = %s; want done", current.Status)
+	}
+
+	// A status that has since left the workflow cannot come back either.
+	w, err = NewWorkflow(&config.Workflow{
+		Statuses: []config.Status{{Name: "todo"}, {Name: "done", Terminal: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	UseWorkflow(w)
+	_, err = tm.RestoreRevision(task.ID, 2)
+	if err == nil || !strings.Contains(err.Error(), "no longer in the workflow") {
+		t.Fatalf("RestoreRevision error = %v; want an unknown status", err)
+	}
+}
+
+func TestUndeleteTaskNoteTrashed(t *testing.T) {
+	tm := NewTaskManagerWithStore(storage.NewMemoryStore())
+	for _, noteTrashed := range []bool{false, true} {
+		task, err := tm.CreateTask("tidy desk", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tm.SetNote(task.ID, "7", 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := tm.DeleteTask(task.ID, noteTrashed); err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := tm.UndeleteTask(task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.NoteTrashed != noteTrashed || entry.NoteID != "7" {
+			t.Errorf("UndeleteTask = note %q, trashed %v; want note 7, trashed %v", entry.NoteID, entry.NoteTrashed, noteTrashed)
+		}
+		if got, err := tm.GetTask(task.ID); err != nil || got.NoteID != "7" {
+			t.Errorf("restored task = %+v, %v; want it linked to note 7", got, err)
+		}
+	}
+}
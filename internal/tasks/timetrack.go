@@ -0,0 +1,239 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// TimeEntry is one interval of work on a task.
+type TimeEntry struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Tracked returns the time logged on the task, including a running timer.
+func (t *Task) Tracked(now time.Time) time.Duration {
+	var total time.Duration
+	for _, entry := range t.TimeLog {
+		total += entry.Duration
+	}
+	if t.StartedAt != nil {
+		total += now.Sub(*t.StartedAt)
+	}
+	return total
+}
+
+// entries returns the task's time log with a running timer counted up to now.
+func (t *Task) entries(now time.Time) []TimeEntry {
+	entries := t.TimeLog
+	if t.StartedAt != nil {
+		entries = append(entries[:len(entries):len(entries)], TimeEntry{Start: *t.StartedAt, Duration: now.Sub(*t.StartedAt)})
+	}
+	return entries
+}
+
+// RunningTimer returns the task whose timer is running, or nil.
+func (tm *TaskManager) RunningTimer() (*Task, error) {
+	taskList, err := tm.ListTasks(Filter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range taskList {
+		if task.StartedAt != nil {
+			return &task, nil
+		}
+	}
+	return nil, nil
+}
+
+// Start begins timing work on a task. Only one timer may run at a time; the
+// check and the save happen under the store lock so two concurrent starts
+// cannot both succeed.
+func (tm *TaskManager) Start(id string) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		if task.IsTerminal() {
+			return fmt.Errorf("task %s is %s", id, task.Status)
+		}
+
+		running, err := tm.RunningTimer()
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			return fmt.Errorf("a timer is already running on task %s (%s); stop it first", running.ID, running.Name)
+		}
+
+		now := time.Now()
+		task.StartedAt = &now
+		task.UpdatedAt = now
+		return nil
+	})
+}
+
+// Stop ends the running timer on a task, or on whichever task has one when id
+// is empty, and logs the elapsed time.
+func (tm *TaskManager) Stop(id string) (*Task, *TimeEntry, error) {
+	var (
+		task  Task
+		entry TimeEntry
+	)
+	err := tm.update(func(tx *TaskManager) error {
+		if id == "" {
+			running, err := tx.RunningTimer()
+			if err != nil {
+				return err
+			}
+			if running == nil {
+				return fmt.Errorf("no timer is running")
+			}
+			id = running.ID
+		}
+
+		var err error
+		if task, err = tx.loadTask(id); err != nil {
+			return err
+		}
+		if task.StartedAt == nil {
+			return fmt.Errorf("no timer is running on task %s", id)
+		}
+
+		entry = stopTimer(&task, time.Now())
+		task.UpdatedAt = time.Now()
+
+		return tx.saveTask(&task)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &task, &entry, nil
+}
+
+func parseTimeEntry(value string) (TimeEntry, error) {
+	start, duration, ok := strings.Cut(value, " ")
+	if !ok {
+		return TimeEntry{}, fmt.Errorf("invalid time entry: %q", value)
+	}
+	at, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return TimeEntry{}, fmt.Errorf("invalid time entry start: %w", err)
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return TimeEntry{}, fmt.Errorf("invalid time entry duration: %w", err)
+	}
+	return TimeEntry{Start: at, Duration: d}, nil
+}
+
+func stopTimer(task *Task, now time.Time) TimeEntry {
+	entry := TimeEntry{Start: *task.StartedAt, Duration: now.Sub(*task.StartedAt).Round(time.Second)}
+	task.TimeLog = append(task.TimeLog, entry)
+	task.StartedAt = nil
+	return entry
+}
+
+// LogTime records d of work on a task, ending now.
+func (tm *TaskManager) LogTime(id string, d time.Duration) (*Task, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+
+	return tm.modify(id, func(task *Task) error {
+		now := time.Now()
+		task.TimeLog = append(task.TimeLog, TimeEntry{Start: now.Add(-d), Duration: d})
+		task.UpdatedAt = now
+		return nil
+	})
+}
+
+func (tm *TaskManager) SetEstimate(id string, estimate time.Duration) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		task.Estimate = estimate
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// TimeGroups are the ways TimeReport can break down tracked time.
+var TimeGroups = []string{"task", "day", "tag"}
+
+type TimeFilter struct {
+	Since   time.Time
+	Until   time.Time
+	Project string
+}
+
+// TimeBucket is the time tracked under one key of a report: a task ID, a day
+// (2006-01-02) or a tag. Task buckets also carry the task.
+type TimeBucket struct {
+	Key      string
+	Task     *Task
+	Duration time.Duration
+}
+
+// TimeReport totals tracked time per task, day or tag. Entries are counted
+// by their start time; tasks without tags are reported under an empty tag.
+func (tm *TaskManager) TimeReport(filter TimeFilter, by string) ([]TimeBucket, error) {
+	switch by {
+	case "task", "day", "tag":
+	default:
+		return nil, fmt.Errorf("invalid grouping: %s (must be: %s)", by, strings.Join(TimeGroups, ", "))
+	}
+
+	taskList, err := tm.ListTasks(Filter{Project: filter.Project})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	buckets := make(map[string]*TimeBucket)
+	add := func(key string, task *Task, d time.Duration) {
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &TimeBucket{Key: key, Task: task}
+			buckets[key] = bucket
+		}
+		bucket.Duration += d
+	}
+
+	for i := range taskList {
+		task := &taskList[i]
+		for _, entry := range task.entries(now) {
+			if !filter.Since.IsZero() && entry.Start.Before(filter.Since) {
+				continue
+			}
+			if !filter.Until.IsZero() && !entry.Start.Before(filter.Until) {
+				continue
+			}
+			switch by {
+			case "task":
+				add(task.ID, task, entry.Duration)
+			case "day":
+				add(entry.Start.Local().Format("2006-01-02"), nil, entry.Duration)
+			case "tag":
+				if len(task.Tags) == 0 {
+					add("", nil, entry.Duration)
+				}
+				for _, tag := range task.Tags {
+					add(tag, nil, entry.Duration)
+				}
+			}
+		}
+	}
+
+	report := make([]TimeBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		report = append(report, *bucket)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if by == "task" {
+			return storage.LessID(report[i].Key, report[j].Key)
+		}
+		return report[i].Key < report[j].Key
+	})
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// slowStore delays reads so that unsynchronised read-modify-write cycles
+// overlap.
+type slowStore struct {
+	storage.Store
+}
+
+func (s slowStore) Get(id string) ([]byte, error) {
+	time.Sleep(time.Millisecond)
+	return s.Store.Get(id)
+}
+
+func (s slowStore) Update(fn func(tx storage.Store) error) error {
+	return s.Store.Update(func(tx storage.Store) error {
+		return fn(slowStore{tx})
+	})
+}
+
+func TestStartAllowsOneTimer(t *testing.T) {
+	tm := NewTaskManagerWithStore(slowStore{storage.NewMemoryStore()})
+	var ids []string
+	for i := 0; i < 8; i++ {
+		task, err := tm.CreateTask("task", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.ID)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		started int
+	)
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if _, err := tm.Start(id); err == nil {
+				mu.Lock()
+				started++
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if started != 1 {
+		t.Fatalf("%d concurrent starts succeeded; want 1", started)
+	}
+	running, err := tm.RunningTimer()
+	if err != nil || running == nil {
+		t.Fatalf("RunningTimer = %v, %v; want the started task", running, err)
+	}
+	if _, _, err := tm.Stop(""); err != nil {
+		t.Fatal(err)
+	}
+	if running, _ := tm.RunningTimer(); running != nil {
+		t.Fatalf("timer still running on task %s after Stop", running.ID)
+	}
+}
@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Transition records one status change of a task.
+type Transition struct {
+	At      time.Time `json:"at"`
+	From    Status    `json:"from"`
+	To      Status    `json:"to"`
+	Comment string    `json:"comment,omitempty"`
+}
+
+// TransitionEvent is a transition together with the task it belongs to.
+type TransitionEvent struct {
+	Task       Task
+	Transition Transition
+}
+
+type ReportFilter struct {
+	Status Status
+	Since  time.Time
+	Until  time.Time
+}
+
+func (f ReportFilter) Match(tr Transition) bool {
+	if f.Status != "" && tr.To != f.Status {
+		return false
+	}
+	if !f.Since.IsZero() && tr.At.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !tr.At.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Report returns every recorded transition matching filter, oldest first.
+func (tm *TaskManager) Report(filter ReportFilter) ([]TransitionEvent, error) {
+	taskList, err := tm.ListTasks(Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []TransitionEvent
+	for _, task := range taskList {
+		for _, tr := range task.Transitions {
+			if filter.Match(tr) {
+				events = append(events, TransitionEvent{Task: task, Transition: tr})
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Transition.At.Before(events[j].Transition.At)
+	})
+	return events, nil
+}
+
+// LastTransition returns the most recent change into status, or nil.
+func (t *Task) LastTransition(status Status) *Transition {
+	for i := len(t.Transitions) - 1; i >= 0; i-- {
+		if t.Transitions[i].To == status {
+			return &t.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// parseTransition reads a "Transition:" header value of the form
+// "<RFC3339> <from> <to> [comment]".
+func parseTransition(value string) (Transition, error) {
+	parts := strings.SplitN(value, " ", 4)
+	if len(parts) < 3 {
+		return Transition{}, fmt.Errorf("invalid transition: %q", value)
+	}
+	at, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return Transition{}, fmt.Errorf("invalid transition timestamp: %w", err)
+	}
+	tr := Transition{At: at, From: Status(parts[1]), To: Status(parts[2])}
+	if len(parts) == 4 {
+		tr.Comment = parts[3]
+	}
+	return tr, nil
+}
+
+func formatTransition(tr Transition) string {
+	value := fmt.Sprintf("%s %s %s", tr.At.Format(time.RFC3339), tr.From, tr.To)
+	if tr.Comment != "" {
+		value += " " + tr.Comment
+	}
+	return value
+}
+
+// cleanComment folds a comment onto one line so it fits in a header.
+func cleanComment(comment string) string {
+	return strings.Join(strings.Fields(comment), " ")
+}
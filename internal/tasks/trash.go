@@ -0,0 +1,146 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+const trashDir = ".trash"
+
+type TrashedTask struct {
+	Task
+	DeletedAt time.Time `json:"deleted_at"`
+	// NoteTrashed is set when the task's linked note was moved to the trash
+	// along with it.
+	NoteTrashed bool `json:"note_trashed,omitempty"`
+}
+
+// noteTrashedHeader follows the Deleted header of a trash entry whose linked
+// note was deleted with the task.
+const noteTrashedHeader = "NoteTrashed: true"
+
+func (tm *TaskManager) trash() storage.Store {
+	return tm.store.Sub(trashDir)
+}
+
+func (tm *TaskManager) trashRecord(id string, data []byte, noteTrashed bool) error {
+	entry := fmt.Sprintf("Deleted: %s\n", time.Now().Format(time.RFC3339))
+	if noteTrashed {
+		entry += noteTrashedHeader + "\n"
+	}
+	entry += string(data)
+	if err := tm.trash().Put(id, []byte(entry)); err != nil {
+		return fmt.Errorf("failed to move task to trash: %w", err)
+	}
+	return nil
+}
+
+func (tm *TaskManager) loadTrashed(id string) (TrashedTask, []byte, error) {
+	data, err := tm.trash().Get(id)
+	if err != nil {
+		return TrashedTask{}, nil, fmt.Errorf("failed to read trashed task: %w", err)
+	}
+
+	header, record, found := strings.Cut(string(data), "\n")
+	if !found || !strings.HasPrefix(header, "Deleted: ") {
+		return TrashedTask{}, nil, fmt.Errorf("invalid trash entry")
+	}
+
+	deletedAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(header, "Deleted: "))
+	if err != nil {
+		return TrashedTask{}, nil, fmt.Errorf("failed to parse deleted timestamp: %w", err)
+	}
+
+	noteTrashed := false
+	if rest, found := strings.CutPrefix(record, noteTrashedHeader+"\n"); found {
+		record, noteTrashed = rest, true
+	}
+
+	task, err := parseTask(id, []byte(record))
+	if err != nil {
+		return TrashedTask{}, nil, err
+	}
+
+	return TrashedTask{Task: task, DeletedAt: deletedAt, NoteTrashed: noteTrashed}, []byte(record), nil
+}
+
+func (tm *TaskManager) ListTrash() ([]TrashedTask, error) {
+	ids, err := tm.trash().List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var trashed []TrashedTask
+	for _, id := range ids {
+		entry, _, err := tm.loadTrashed(id)
+		if err != nil {
+			continue
+		}
+		trashed = append(trashed, entry)
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.Before(trashed[j].DeletedAt)
+	})
+
+	return trashed, nil
+}
+
+// UndeleteTask moves a task back out of the trash. The returned entry tells
+// whether its linked note was trashed with it.
+func (tm *TaskManager) UndeleteTask(id string) (*TrashedTask, error) {
+	entry, record, err := tm.loadTrashed(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tm.update(func(tx *TaskManager) error {
+		if _, err := tx.store.Get(id); err == nil {
+			return fmt.Errorf("task %s already exists", id)
+		}
+
+		if err := tx.store.Put(id, record); err != nil {
+			return fmt.Errorf("failed to restore task: %w", err)
+		}
+		if err := tx.trash().Delete(id); err != nil {
+			return fmt.Errorf("failed to remove task from trash: %w", err)
+		}
+		return tx.indexTask(&entry.Task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// EmptyTrash permanently removes trashed tasks, along with their revision
+// history, that were deleted more than olderThan ago. A zero olderThan
+// removes everything.
+func (tm *TaskManager) EmptyTrash(olderThan time.Duration) ([]TrashedTask, error) {
+	trashed, err := tm.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var removed []TrashedTask
+	for _, entry := range trashed {
+		if olderThan > 0 && entry.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := tm.trash().Delete(entry.ID); err != nil {
+			return removed, fmt.Errorf("failed to remove task %s from trash: %w", entry.ID, err)
+		}
+		if err := tm.history(entry.ID).Clear(); err != nil {
+			return removed, fmt.Errorf("failed to delete history of task %s: %w", entry.ID, err)
+		}
+		removed = append(removed, entry)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+)
+
+type TreeNode struct {
+	Task     Task
+	Children []*TreeNode
+}
+
+// BuildTree arranges tasks by ParentID. Tasks whose parent is not in the
+// list become roots, so filtered lists still render every task.
+func BuildTree(taskList []Task) []*TreeNode {
+	nodes := make(map[string]*TreeNode, len(taskList))
+	for _, task := range taskList {
+		nodes[task.ID] = &TreeNode{Task: task}
+	}
+
+	var roots []*TreeNode
+	for _, task := range taskList {
+		node := nodes[task.ID]
+		if parent, ok := nodes[task.ParentID]; ok && task.ParentID != task.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Progress counts the completed descendants of n against all descendants
+// that were not abandoned, i.e. left in another terminal status.
+func (n *TreeNode) Progress() (done, total int) {
+	for _, child := range n.Children {
+		switch {
+		case child.Task.Status == workflow.Completed():
+			done++
+			total++
+		case child.Task.IsTerminal():
+		default:
+			total++
+		}
+		childDone, childTotal := child.Progress()
+		done += childDone
+		total += childTotal
+	}
+	return done, total
+}
+
+func (tm *TaskManager) Children(id string) ([]Task, error) {
+	taskList, err := tm.ListTasks(Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var children []Task
+	for _, task := range taskList {
+		if task.ParentID == id {
+			children = append(children, task)
+		}
+	}
+	return children, nil
+}
+
+// OpenDescendants returns every unfinished task below id, depth first.
+func (tm *TaskManager) OpenDescendants(id string) ([]Task, error) {
+	taskList, err := tm.ListTasks(Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var open []Task
+	var walk func(node *TreeNode)
+	walk = func(node *TreeNode) {
+		for _, child := range node.Children {
+			walk(child)
+			if !child.Task.IsTerminal() {
+				open = append(open, child.Task)
+			}
+		}
+	}
+	for _, root := range BuildTree(taskList) {
+		if node := findNode(root, id); node != nil {
+			walk(node)
+			break
+		}
+	}
+	return open, nil
+}
+
+func findNode(node *TreeNode, id string) *TreeNode {
+	if node.Task.ID == id {
+		return node
+	}
+	for _, child := range node.Children {
+		if found := findNode(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// SetParent makes parentID the parent of id, refusing changes that would
+// create a cycle. An empty parentID detaches the task.
+func (tm *TaskManager) SetParent(id string, parentID string) (*Task, error) {
+	return tm.modify(id, func(task *Task) error {
+		if err := tm.checkParent(id, parentID); err != nil {
+			return err
+		}
+
+		task.ParentID = parentID
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (tm *TaskManager) checkParent(id string, parentID string) error {
+	seen := make(map[string]bool)
+	for ancestor := parentID; ancestor != "" && !seen[ancestor]; {
+		seen[ancestor] = true
+		if ancestor == id {
+			return fmt.Errorf("task %s cannot be a subtask of itself or its own subtasks", id)
+		}
+		parent, err := tm.loadTask(ancestor)
+		if err != nil {
+			if ancestor == parentID {
+				return fmt.Errorf("parent task not found: %s", parentID)
+			}
+			return nil
+		}
+		ancestor = parent.ParentID
+	}
+	return nil
+}
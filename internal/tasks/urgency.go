@@ -0,0 +1,130 @@
+package tasks
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+type Priority string
+
+const (
+	PriorityNone   Priority = ""
+	PriorityHigh   Priority = "H"
+	PriorityMedium Priority = "M"
+	PriorityLow    Priority = "L"
+)
+
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(s) {
+	case "h", "high":
+		return PriorityHigh, nil
+	case "m", "medium", "med":
+		return PriorityMedium, nil
+	case "l", "low":
+		return PriorityLow, nil
+	case "", "none":
+		return PriorityNone, nil
+	}
+	return PriorityNone, fmt.Errorf("invalid priority: %s (must be: H, M, L or none)", s)
+}
+
+func (p Priority) rank() int {
+	switch p {
+	case PriorityHigh:
+		return 3
+	case PriorityMedium:
+		return 2
+	case PriorityLow:
+		return 1
+	}
+	return 0
+}
+
+const (
+	urgencyPriorityHigh   = 6.0
+	urgencyPriorityMedium = 3.9
+	urgencyPriorityLow    = 1.8
+	urgencyDue            = 12.0
+	urgencyAge            = 2.0
+	urgencyMaxAgeDays     = 365.0
+)
+
+// Urgency scores how pressing an open task is from its priority, due date
+// and age; tasks that are no longer open score zero. Higher is more urgent.
+func (t *Task) Urgency(now time.Time) float64 {
+	if t.IsTerminal() {
+		return 0
+	}
+
+	var score float64
+	switch t.Priority {
+	case PriorityHigh:
+		score += urgencyPriorityHigh
+	case PriorityMedium:
+		score += urgencyPriorityMedium
+	case PriorityLow:
+		score += urgencyPriorityLow
+	}
+
+	if t.DueAt != nil {
+		score += urgencyDue * dueFactor(t.DueAt.Sub(now))
+	}
+
+	ageDays := now.Sub(t.CreatedAt).Hours() / 24
+	score += urgencyAge * math.Min(math.Max(ageDays, 0)/urgencyMaxAgeDays, 1)
+
+	return math.Round(score*100) / 100
+}
+
+// dueFactor ramps from 0.2 for tasks due two weeks or more away up to 1.0
+// for tasks a week or more overdue.
+func dueFactor(untilDue time.Duration) float64 {
+	days := untilDue.Hours() / 24
+	switch {
+	case days <= -7:
+		return 1.0
+	case days >= 14:
+		return 0.2
+	default:
+		return 0.2 + 0.8*(14-days)/21
+	}
+}
+
+var SortKeys = []string{"id", "urgency", "due", "priority", "created", "updated", "name"}
+
+func SortTasks(tasks []Task, key string, now time.Time) error {
+	var less func(a, b *Task) bool
+	switch key {
+	case "", "id":
+		less = func(a, b *Task) bool { return storage.LessID(a.ID, b.ID) }
+	case "urgency":
+		less = func(a, b *Task) bool { return a.Urgency(now) > b.Urgency(now) }
+	case "due":
+		less = func(a, b *Task) bool {
+			if a.DueAt == nil || b.DueAt == nil {
+				return a.DueAt != nil && b.DueAt == nil
+			}
+			return a.DueAt.Before(*b.DueAt)
+		}
+	case "priority":
+		less = func(a, b *Task) bool { return a.Priority.rank() > b.Priority.rank() }
+	case "created":
+		less = func(a, b *Task) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "updated":
+		less = func(a, b *Task) bool { return a.UpdatedAt.After(b.UpdatedAt) }
+	case "name":
+		less = func(a, b *Task) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	default:
+		return fmt.Errorf("invalid sort key: %s (must be one of: %s)", key, strings.Join(SortKeys, ", "))
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return less(&tasks[i], &tasks[j])
+	})
+	return nil
+}
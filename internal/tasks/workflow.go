@@ -0,0 +1,203 @@
+package tasks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wltechblog/notes/internal/config"
+)
+
+type StatusDef struct {
+	Name        Status
+	Terminal    bool
+	Description string
+}
+
+// Workflow is the set of statuses a task can be in and the transitions
+// allowed between them. Terminal statuses mark a task as finished: it no
+// longer blocks dependents, is never overdue and scores no urgency. Of the
+// terminal statuses, only the completed one counts towards progress.
+type Workflow struct {
+	statuses    []StatusDef
+	transitions map[Status][]Status
+	initial     Status
+	completed   Status
+}
+
+// DefaultWorkflow is open, completed and abandoned with any transition
+// allowed.
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		statuses: []StatusDef{
+			{Name: StatusOpen},
+			{Name: StatusCompleted, Terminal: true},
+			{Name: StatusAbandoned, Terminal: true},
+		},
+		initial:   StatusOpen,
+		completed: StatusCompleted,
+	}
+}
+
+// NewWorkflow builds a workflow from its configuration, or returns the
+// default workflow if cfg is nil.
+func NewWorkflow(cfg *config.Workflow) (*Workflow, error) {
+	if cfg == nil {
+		return DefaultWorkflow(), nil
+	}
+
+	w := &Workflow{}
+	for _, s := range cfg.Statuses {
+		name := Status(strings.TrimSpace(s.Name))
+		if name == "" || strings.ContainsAny(string(name), " \t\n") {
+			return nil, fmt.Errorf("invalid status name in workflow: %q", s.Name)
+		}
+		if w.Valid(name) {
+			return nil, fmt.Errorf("duplicate status in workflow: %s", name)
+		}
+		w.statuses = append(w.statuses, StatusDef{Name: name, Terminal: s.Terminal, Description: s.Description})
+		if w.initial == "" && !s.Terminal {
+			w.initial = name
+		}
+		if w.completed == "" && s.Terminal {
+			w.completed = name
+		}
+	}
+
+	if cfg.Initial != "" {
+		w.initial = Status(cfg.Initial)
+	}
+	if cfg.Completed != "" {
+		w.completed = Status(cfg.Completed)
+	}
+	if w.initial == "" || !w.Valid(w.initial) || w.IsTerminal(w.initial) {
+		return nil, fmt.Errorf("workflow needs a non-terminal initial status")
+	}
+	if w.completed == "" || !w.Valid(w.completed) || !w.IsTerminal(w.completed) {
+		return nil, fmt.Errorf("workflow needs a terminal completed status")
+	}
+
+	if len(cfg.Transitions) > 0 {
+		w.transitions = make(map[Status][]Status)
+		for from, targets := range cfg.Transitions {
+			if !w.Valid(Status(from)) {
+				return nil, fmt.Errorf("unknown status in workflow transitions: %s", from)
+			}
+			for _, to := range targets {
+				if !w.Valid(Status(to)) {
+					return nil, fmt.Errorf("unknown status in workflow transitions: %s", to)
+				}
+				w.transitions[Status(from)] = append(w.transitions[Status(from)], Status(to))
+			}
+		}
+	}
+
+	return w, nil
+}
+
+var workflow = DefaultWorkflow()
+
+// UseWorkflow replaces the workflow used by every TaskManager.
+func UseWorkflow(w *Workflow) {
+	workflow = w
+}
+
+func CurrentWorkflow() *Workflow {
+	return workflow
+}
+
+func (w *Workflow) Statuses() []StatusDef {
+	return w.statuses
+}
+
+func (w *Workflow) Names() []Status {
+	names := make([]Status, len(w.statuses))
+	for i, s := range w.statuses {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func (w *Workflow) Initial() Status {
+	return w.initial
+}
+
+func (w *Workflow) Completed() Status {
+	return w.completed
+}
+
+func (w *Workflow) Valid(status Status) bool {
+	_, ok := w.lookup(status)
+	return ok
+}
+
+// IsTerminal reports whether status finishes a task. Statuses the workflow
+// does not know about are treated as unfinished.
+func (w *Workflow) IsTerminal(status Status) bool {
+	def, ok := w.lookup(status)
+	return ok && def.Terminal
+}
+
+// Targets returns the statuses a task in from may move to. A status without
+// configured transitions may move to any other.
+func (w *Workflow) Targets(from Status) []Status {
+	if targets, ok := w.transitions[from]; ok {
+		return targets
+	}
+	var targets []Status
+	for _, s := range w.statuses {
+		if s.Name != from {
+			targets = append(targets, s.Name)
+		}
+	}
+	return targets
+}
+
+func (w *Workflow) CanTransition(from, to Status) bool {
+	if from == to {
+		return true
+	}
+	for _, target := range w.Targets(from) {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe lists the statuses, e.g. "open, in-progress, done (terminal)".
+func (w *Workflow) Describe() string {
+	names := make([]string, len(w.statuses))
+	for i, s := range w.statuses {
+		names[i] = string(s.Name)
+		if s.Terminal {
+			names[i] += " (terminal)"
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (w *Workflow) lookup(status Status) (StatusDef, bool) {
+	for _, s := range w.statuses {
+		if s.Name == status {
+			return s, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+func joinStatuses(statuses []Status) string {
+	if len(statuses) == 0 {
+		return "none"
+	}
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
+
+// IsTerminal reports whether the task is in a terminal status of the current
+// workflow.
+func (t *Task) IsTerminal() bool {
+	return workflow.IsTerminal(t.Status)
+}
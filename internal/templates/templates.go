@@ -0,0 +1,111 @@
+// Package templates renders records with user-supplied text/template
+// formats, providing helpers for dates, truncation, padding and colour.
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+var colors = map[string]string{
+	"bold":    "1",
+	"dim":     "2",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+}
+
+// unescaper turns the escapes people type on the command line into the
+// characters they stand for.
+var unescaper = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+
+// Parse parses text as a template named name. The escapes \t, \n and \\ in
+// text stand for a tab, newline and backslash. With color false the color
+// helper leaves its text unchanged.
+func Parse(name, text string, color bool) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs(color)).Option("missingkey=error").Parse(unescaper.Replace(text))
+}
+
+// Funcs returns the helpers available to templates:
+//
+//	date LAYOUT TIME   formats a time.Time or *time.Time with a Go layout; nil gives ""
+//	trunc N TEXT       keeps the first N characters of text, adding "..." if it was longer
+//	pad N TEXT         pads text with spaces to N characters; a negative N aligns right
+//	color NAME TEXT    wraps text in an ANSI colour, such as red or bold
+//	join SEP LIST      joins a list of strings
+//	upper, lower       change case
+func Funcs(color bool) template.FuncMap {
+	return template.FuncMap{
+		"date": formatDate,
+		"trunc": func(n int, text any) string {
+			return Truncate(n, fmt.Sprint(text))
+		},
+		"pad": func(n int, text any) string {
+			return Pad(n, fmt.Sprint(text))
+		},
+		"color": func(name string, text any) (string, error) {
+			code, ok := colors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color: %s", name)
+			}
+			s := fmt.Sprint(text)
+			if !color {
+				return s, nil
+			}
+			return "\x1b[" + code + "m" + s + "\x1b[0m", nil
+		},
+		"join": func(sep string, list []string) string {
+			return strings.Join(list, sep)
+		},
+		"upper": func(text any) string { return strings.ToUpper(fmt.Sprint(text)) },
+		"lower": func(text any) string { return strings.ToLower(fmt.Sprint(text)) },
+	}
+}
+
+func formatDate(layout string, value any) (string, error) {
+	switch t := value.(type) {
+	case time.Time:
+		return t.Format(layout), nil
+	case *time.Time:
+		if t == nil {
+			return "", nil
+		}
+		return t.Format(layout), nil
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("date: %v is not a time", value)
+}
+
+// Truncate keeps the first n runes of s, adding "..." when s was longer. It
+// never splits a multi-byte character.
+func Truncate(n int, s string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:max(n, 0)]) + "..."
+}
+
+// Pad pads s with spaces to n runes, on the right, or on the left when n is
+// negative. Longer strings are returned unchanged.
+func Pad(n int, s string) string {
+	width := n
+	if width < 0 {
+		width = -width
+	}
+	fill := width - utf8.RuneCountInString(s)
+	if fill <= 0 {
+		return s
+	}
+	if n < 0 {
+		return strings.Repeat(" ", fill) + s
+	}
+	return s + strings.Repeat(" ", fill)
+}
@@ -0,0 +1,120 @@
+// Package views stores named, saved queries ("views") alongside the notes or
+// tasks they run against.
+package views
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wltechblog/notes/internal/record"
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+// Dir is the namespace views are kept in within a note or task store.
+const Dir = ".views"
+
+// View is a saved query with its display options. Empty Sort and Columns
+// mean the list command's defaults.
+type View struct {
+	Name    string   `json:"name"`
+	Query   string   `json:"query"`
+	Sort    string   `json:"sort,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid view name: %q (use letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+type Views struct {
+	store storage.Store
+}
+
+func New(store storage.Store) *Views {
+	return &Views{store: store}
+}
+
+func (v *Views) Save(view *View) error {
+	if err := ValidateName(view.Name); err != nil {
+		return err
+	}
+	if err := v.store.Put(view.Name, formatView(view)); err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+	return nil
+}
+
+func (v *Views) Get(name string) (*View, error) {
+	data, err := v.store.Get(name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("view not found: %s", name)
+		}
+		return nil, fmt.Errorf("failed to read view: %w", err)
+	}
+	return parseView(data)
+}
+
+func (v *Views) List() ([]View, error) {
+	names, err := v.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read views: %w", err)
+	}
+
+	var list []View
+	for _, name := range names {
+		view, err := v.Get(name)
+		if err != nil {
+			continue
+		}
+		list = append(list, *view)
+	}
+	return list, nil
+}
+
+func (v *Views) Delete(name string) error {
+	if err := v.store.Delete(name); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("view not found: %s", name)
+		}
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
+
+func parseView(data []byte) (*View, error) {
+	fields, _, err := record.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid view format: %w", err)
+	}
+
+	view := &View{
+		Name:  record.Get(fields, "Name"),
+		Query: record.Get(fields, "Query"),
+		Sort:  record.Get(fields, "Sort"),
+	}
+	if columns := record.Get(fields, "Columns"); columns != "" {
+		view.Columns = strings.Split(columns, ",")
+	}
+	return view, nil
+}
+
+func formatView(view *View) []byte {
+	var content string
+	content += fmt.Sprintf("Query: %s\n", view.Query)
+	if view.Sort != "" {
+		content += fmt.Sprintf("Sort: %s\n", view.Sort)
+	}
+	if len(view.Columns) > 0 {
+		content += fmt.Sprintf("Columns: %s\n", strings.Join(view.Columns, ","))
+	}
+	content += fmt.Sprintf("Name: %s\n", view.Name)
+	return []byte(content)
+}
@@ -2,14 +2,25 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+)
+
+var (
+	listTags    []string
+	listNotTags []string
+	listQuery   string
+	listFormat  string
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all notes",
+	Long: "List all notes. Use --query to filter with the query language, e.g. 'tag:work AND created>-7d', " +
+		"and --format to print each note with a template such as '{{.ID}}\\t{{.Name}}' (see README)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !noteMode {
 			return fmt.Errorf("this command is only available for notes, use 'task list' instead")
@@ -19,30 +30,62 @@ var listCmd = &cobra.Command{
 			return err
 		}
 
-		notesList, err := nm.ListNotes()
+		q, err := notes.ParseQuery(listQuery)
 		if err != nil {
 			return err
 		}
 
+		notesList, err := nm.ListNotes(notes.Filter{Tags: listTags, NotTags: listNotTags, Query: q})
+		if err != nil {
+			return err
+		}
+
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, notesList, noteOutputColumns)
+		}
+
+		if listFormat != "" {
+			tmpl, err := loadTemplate(listFormat, noteTemplates())
+			if err != nil {
+				return err
+			}
+			for _, note := range notesList {
+				if err := printTemplate(tmpl, noteTemplateData{Note: note}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
 		if len(notesList) == 0 {
 			fmt.Println("No notes found")
 			return nil
 		}
 
 		for _, note := range notesList {
-			fmt.Printf("%s | %s | Created: %s | Updated: %s\n",
-				note.ID,
-				note.Name,
-				note.CreatedAt.Format("2006-01-02 15:04:05"),
-				note.UpdatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Println(formatNoteLine(&note))
 		}
 
 		return nil
 	},
 }
 
+func formatNoteLine(note *notes.Note) string {
+	return fmt.Sprintf("%s | %s%s | Created: %s | Updated: %s",
+		note.ID,
+		note.Name,
+		formatTagList(note.Tags),
+		note.CreatedAt.Format("2006-01-02 15:04:05"),
+		note.UpdatedAt.Format("2006-01-02 15:04:05"))
+}
+
 func init() {
 	if noteMode {
-		rootCmd.AddCommand(listCmd)
+		listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "Only show notes with this tag (repeatable)")
+		listCmd.Flags().StringSliceVar(&listNotTags, "not-tag", nil, "Hide notes with this tag (repeatable)")
+		listCmd.Flags().StringVarP(&listQuery, "query", "q", "", "Only show notes matching this query")
+		listCmd.Flags().StringVar(&listFormat, "format", "",
+			"Print each note with a Go template, or a named template ("+templateNames(noteTemplates())+")")
+		rootCmd.AddCommand(supportsOutput(listCmd))
 	}
 }
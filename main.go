@@ -34,8 +34,12 @@ func getRootCommand() *cobra.Command {
 var rootCmd = getRootCommand()
 
 func main() {
+	if configErr != nil {
+		fmt.Fprintln(os.Stderr, configErr)
+		os.Exit(1)
+	}
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
@@ -2,16 +2,20 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/tags"
 )
 
 var newCmd = &cobra.Command{
-	Use:     "new [name]",
+	Use:     "new [name] [+tag]...",
 	Aliases: []string{"create"},
 	Short:   "Create a new note",
-	Args:    cobra.MaximumNArgs(1),
+	Long:    "Create a new note. Arguments starting with '+' are added as tags; the rest form the name",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !noteMode {
 			return fmt.Errorf("this command is only available for notes, use 'task new' instead")
@@ -21,13 +25,14 @@ var newCmd = &cobra.Command{
 			return err
 		}
 
-		name := ""
-		if len(args) > 0 {
-			name = args[0]
+		tagList, nameArgs, err := tags.Split(args)
+		if err != nil {
+			return err
 		}
+		name := strings.Join(nameArgs, " ")
 
-		note, err := nm.CreateNote(name, "")
-		if err != nil {
+		note := &notes.Note{Name: name, Tags: tagList}
+		if err := nm.Create(note); err != nil {
 			return err
 		}
 
@@ -36,18 +41,24 @@ var newCmd = &cobra.Command{
 		}
 
 		if note.Content == "" {
-			if err := nm.DeleteNote(note.ID); err != nil {
+			if err := nm.PurgeNote(note.ID); err != nil {
 				return err
 			}
-			fmt.Println("Note not saved (empty content)")
-			return nil
+			return exitWith(cmd, exitError, "Note not saved (empty content)")
 		}
 
-		_, err = nm.UpdateNote(note.ID, note.Content)
-		if err != nil {
+		if _, err := nm.UpdateNote(note.ID, note.Content); err != nil {
 			return err
 		}
 
+		if machineOutput() {
+			// Read the note back so timestamps match what was stored.
+			saved, err := nm.GetNote(note.ID)
+			if err != nil {
+				return err
+			}
+			return output.One(os.Stdout, outputFormat, *saved, noteOutputColumns)
+		}
 		fmt.Printf("Note created: %s\n", note.ID)
 		return nil
 	},
@@ -55,6 +66,6 @@ var newCmd = &cobra.Command{
 
 func init() {
 	if noteMode {
-		rootCmd.AddCommand(newCmd)
+		rootCmd.AddCommand(supportsOutput(newCmd))
 	}
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+// Exit codes. Errors cobra reports itself, such as unknown flags, also exit
+// with exitError.
+const (
+	exitError    = 1
+	exitNotFound = 2
+)
+
+var (
+	outputFlag   string
+	outputFormat = output.Text
+)
+
+// outputAnnotation marks commands that honour --output.
+const outputAnnotation = "output"
+
+// supportsOutput declares that cmd writes records in the --output format.
+func supportsOutput(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[outputAnnotation] = "true"
+	return cmd
+}
+
+// checkOutput parses --output, rejecting machine-readable formats for
+// commands that only print text.
+func checkOutput(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(outputFlag)
+	if err != nil {
+		return err
+	}
+	if format != output.Text && cmd.Annotations[outputAnnotation] == "" {
+		return fmt.Errorf("--output %s is not supported by '%s'", format, cmd.CommandPath())
+	}
+	if format != output.Text && cmd.Flags().Changed("format") {
+		return fmt.Errorf("--format cannot be used with --output %s", format)
+	}
+	outputFormat = format
+	return nil
+}
+
+// machineOutput reports whether records go to stdout in a machine-readable
+// format, in which case messages for people go to stderr and nothing prompts.
+func machineOutput() bool {
+	return outputFormat != output.Text
+}
+
+// notice prints a message meant for people: to stdout with text output, and
+// to stderr otherwise so it does not mix with the records.
+func notice(format string, args ...any) {
+	if machineOutput() {
+		fmt.Fprintf(os.Stderr, format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// exitCodeError ends a command with a message on stderr and a specific exit
+// code, without the usage text cobra prints for other errors.
+type exitCodeError struct {
+	msg  string
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return e.msg
+}
+
+func exitWith(cmd *cobra.Command, code int, format string, args ...any) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &exitCodeError{msg: fmt.Sprintf(format, args...), code: code}
+}
+
+// exitCode returns the exit status for an error returned by a command.
+func exitCode(err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitError
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var noteOutputColumns = []output.Column[notes.Note]{
+	{Name: "id", Value: func(n notes.Note) string { return n.ID }},
+	{Name: "name", Value: func(n notes.Note) string { return n.Name }},
+	{Name: "tags", Value: func(n notes.Note) string { return strings.Join(n.Tags, ",") }},
+	{Name: "created_at", Value: func(n notes.Note) string { return n.CreatedAt.Format(time.RFC3339) }},
+	{Name: "updated_at", Value: func(n notes.Note) string { return n.UpdatedAt.Format(time.RFC3339) }},
+	{Name: "content", Value: func(n notes.Note) string { return n.Content }},
+}
+
+var taskOutputColumns = []output.Column[tasks.Task]{
+	{Name: "id", Value: func(t tasks.Task) string { return t.ID }},
+	{Name: "name", Value: func(t tasks.Task) string { return t.Name }},
+	{Name: "status", Value: func(t tasks.Task) string { return string(t.Status) }},
+	{Name: "priority", Value: func(t tasks.Task) string { return string(t.Priority) }},
+	{Name: "project", Value: func(t tasks.Task) string { return t.Project }},
+	{Name: "tags", Value: func(t tasks.Task) string { return strings.Join(t.Tags, ",") }},
+	{Name: "parent_id", Value: func(t tasks.Task) string { return t.ParentID }},
+	{Name: "depends_on", Value: func(t tasks.Task) string { return strings.Join(t.DependsOn, ",") }},
+	{Name: "note_id", Value: func(t tasks.Task) string { return t.NoteID }},
+	{Name: "recur", Value: func(t tasks.Task) string { return t.Recur }},
+	{Name: "due_at", Value: func(t tasks.Task) string { return formatOptionalTime(t.DueAt) }},
+	{Name: "scheduled_at", Value: func(t tasks.Task) string { return formatOptionalTime(t.ScheduledAt) }},
+	{Name: "created_at", Value: func(t tasks.Task) string { return t.CreatedAt.Format(time.RFC3339) }},
+	{Name: "updated_at", Value: func(t tasks.Task) string { return t.UpdatedAt.Format(time.RFC3339) }},
+	{Name: "content", Value: func(t tasks.Task) string { return t.Content }},
+}
+
+// resultColumns adds the score to the columns of the searched records.
+func resultColumns[T, R any](columns []output.Column[T], record func(R) T, score func(R) float64) []output.Column[R] {
+	result := make([]output.Column[R], 0, len(columns)+1)
+	for _, column := range columns {
+		value := column.Value
+		result = append(result, output.Column[R]{Name: column.Name, Value: func(r R) string { return value(record(r)) }})
+	}
+	return append(result, output.Column[R]{Name: "score", Value: func(r R) string { return fmt.Sprintf("%g", score(r)) }})
+}
+
+var noteResultColumns = resultColumns(noteOutputColumns,
+	func(r notes.Result) notes.Note { return r.Note },
+	func(r notes.Result) float64 { return r.Score })
+
+var taskResultColumns = resultColumns(taskOutputColumns,
+	func(r tasks.Result) tasks.Task { return r.Task },
+	func(r tasks.Result) float64 { return r.Score })
+
+// statusChange is the record 'task status' writes for each task it moves.
+type statusChange struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	From   string `json:"from"`
+	Status string `json:"status"`
+	// NextID is the next occurrence of a completed recurring task.
+	NextID string `json:"next_id"`
+	// Unblocked lists tasks whose last open dependency this was.
+	Unblocked []string `json:"unblocked"`
+}
+
+var statusChangeColumns = []output.Column[statusChange]{
+	{Name: "id", Value: func(c statusChange) string { return c.ID }},
+	{Name: "name", Value: func(c statusChange) string { return c.Name }},
+	{Name: "from", Value: func(c statusChange) string { return c.From }},
+	{Name: "status", Value: func(c statusChange) string { return c.Status }},
+	{Name: "next_id", Value: func(c statusChange) string { return c.NextID }},
+	{Name: "unblocked", Value: func(c statusChange) string { return strings.Join(c.Unblocked, ",") }},
+}
+
+// deletion is the record 'delete' writes for each note or task moved to the
+// trash.
+type deletion struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var deletionColumns = []output.Column[deletion]{
+	{Name: "type", Value: func(d deletion) string { return d.Type }},
+	{Name: "id", Value: func(d deletion) string { return d.ID }},
+	{Name: "name", Value: func(d deletion) string { return d.Name }},
+}
+
+func init() {
+	names := make([]string, len(output.Formats))
+	for i, f := range output.Formats {
+		names[i] = string(f)
+	}
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", string(output.Text),
+		"Output format for list, search, show, new, status and delete ("+strings.Join(names, ", ")+")")
+	rootCmd.PersistentPreRunE = checkOutput
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var parseDateCmd = &cobra.Command{
+	Use:   "parse-date [expression]",
+	Short: "Show how a date expression resolves",
+	Long: "Show how a date expression resolves in the local timezone. Accepts absolute dates " +
+		"(2026-11-01, 2026-11-01 14:00, RFC3339), today, tomorrow, yesterday, weekday names " +
+		"(friday, next friday, last monday), next week/month/year, offsets (in 3 days, 2 weeks ago, " +
+		"+3d, -7d), sod/eod, sow/eow, som/eom, soy/eoy, and an optional time of day (tomorrow 14:00, friday at 9am)",
+	Args:               cobra.MinimumNArgs(1),
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[0] == "-h" || args[0] == "--help" {
+			return cmd.Help()
+		}
+
+		expr := strings.Join(args, " ")
+		t, err := parseDate(expr)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s -> %s (%s)\n", expr, t.Format("2006-01-02 15:04:05 MST"), t.Weekday())
+		fmt.Println(t.Format(time.RFC3339))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(parseDateCmd)
+}
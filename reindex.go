@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+)
+
+var reindexStem bool
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the search index",
+	Long: "Rebuild the search index from every note. The index is kept up to date as notes change; " +
+		"rebuild it after editing note files outside this tool, or to turn stemming on or off",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task reindex' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		count, err := nm.Reindex(reindexStem)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Indexed %d notes\n", count)
+		return nil
+	},
+}
+
+func init() {
+	if noteMode {
+		reindexCmd.Flags().BoolVar(&reindexStem, "stem", false, "Index words by their stems, so 'deploying' matches 'deployed'")
+		rootCmd.AddCommand(reindexCmd)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [id] [rev]",
+	Short: "Restore a note to an earlier revision",
+	Long:  "Restore a note to an earlier revision. The current version is kept in the note's history",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task restore' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		rev, err := parseRevision(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := nm.GetNote(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+		}
+		if _, err := nm.GetRevision(id, rev); err != nil {
+			return exitWith(cmd, exitNotFound, "Revision %d of note %s not found", rev, id)
+		}
+
+		if _, err := nm.RestoreRevision(id, rev); err != nil {
+			return exitWith(cmd, exitError, "Failed to restore note: %v", err)
+		}
+
+		fmt.Printf("Note %s restored to revision %d\n", id, rev)
+		return nil
+	},
+}
+
+func init() {
+	if noteMode {
+		rootCmd.AddCommand(restoreCmd)
+	}
+}
@@ -2,49 +2,155 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/fuzzy"
 	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/snippet"
+)
+
+var (
+	searchTags    []string
+	searchNotTags []string
+	searchContext int
+	searchRegex   bool
+	searchFuzzy   bool
+	searchScope   string
+	searchFormat  string
 )
 
 var searchCmd = &cobra.Command{
-	Use:   "search [keyword]",
-	Short: "Search notes by keyword",
-	Args:  cobra.ExactArgs(1),
+	Use:   "search [query]...",
+	Short: "Search notes by keyword or query",
+	Long: "Search notes. Plain words and quoted phrases match the name or content; field predicates such as " +
+		"tag:work or created>-7d, AND, OR, NOT (or '-') and parentheses narrow the search further (see README). " +
+		"Results are ranked by relevance using the search index, " +
+		"and show the matching lines of each note with --context lines around them. " +
+		"With --regex the arguments are a regular expression, matched against the parts of the note --scope selects; " +
+		"with --fuzzy they are words that may be misspelled",
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !noteMode {
 			return fmt.Errorf("this command is only available for notes, use 'task search' instead")
 		}
+		if err := checkScope(cmd, searchScope, searchRegex); err != nil {
+			return err
+		}
 		nm, err := notes.NewNoteManager()
 		if err != nil {
 			return err
 		}
 
-		keyword := args[0]
-		notesList, err := nm.SearchNotes(keyword)
-		if err != nil {
-			return err
+		keyword := strings.Join(args, " ")
+		filter := notes.Filter{Tags: searchTags, NotTags: searchNotTags}
+
+		var results []notes.Result
+		var match snippet.Matcher
+		formatScore := func(score float64) string { return fmt.Sprintf("Score: %.2f", score) }
+		switch {
+		case searchRegex:
+			re, err := regexp.Compile(keyword)
+			if err != nil {
+				return fmt.Errorf("invalid regular expression: %w", err)
+			}
+			if results, err = nm.SearchNotesRegex(re, notes.Scope(searchScope), filter); err != nil {
+				return err
+			}
+			if notes.Scope(searchScope) != notes.ScopeName {
+				match = snippet.Regexp(re)
+			}
+			formatScore = func(score float64) string { return fmt.Sprintf("Matches: %.0f", score) }
+		case searchFuzzy:
+			if results, err = nm.SearchNotesFuzzy(keyword, filter); err != nil {
+				return err
+			}
+			match = fuzzyMatcher(keyword)
+		default:
+			if results, err = nm.SearchNotes(keyword, filter); err != nil {
+				return err
+			}
+			words, err := nm.SearchWords(keyword)
+			if err != nil {
+				return err
+			}
+			match = snippet.Words(words)
+		}
+
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, results, noteResultColumns)
+		}
+
+		if searchFormat != "" {
+			tmpl, err := loadTemplate(searchFormat, noteTemplates())
+			if err != nil {
+				return err
+			}
+			for _, result := range results {
+				if err := printTemplate(tmpl, noteTemplateData{Note: result.Note, Score: result.Score}); err != nil {
+					return err
+				}
+			}
+			return nil
 		}
 
-		if len(notesList) == 0 {
+		if len(results) == 0 {
 			fmt.Printf("No notes found matching '%s'\n", keyword)
 			return nil
 		}
 
-		for _, note := range notesList {
-			fmt.Printf("%s | %s | Created: %s | Updated: %s\n",
-				note.ID,
-				note.Name,
-				note.CreatedAt.Format("2006-01-02 15:04:05"),
-				note.UpdatedAt.Format("2006-01-02 15:04:05"))
+		for _, result := range results {
+			fmt.Printf("%s | %s\n", formatNoteLine(&result.Note), formatScore(result.Score))
+			if match != nil {
+				printSnippets(result.Content, match, searchContext)
+			}
 		}
 
 		return nil
 	},
 }
 
+// searchScopes are the values --scope accepts.
+var searchScopes = []string{string(notes.ScopeBoth), string(notes.ScopeName), string(notes.ScopeContent)}
+
+// checkScope rejects an unknown --scope, or one given without --regex.
+func checkScope(cmd *cobra.Command, scope string, regex bool) error {
+	if cmd.Flags().Changed("scope") && !regex {
+		return fmt.Errorf("--scope only applies to --regex searches")
+	}
+	for _, s := range searchScopes {
+		if scope == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid scope %q (valid: %s)", scope, strings.Join(searchScopes, ", "))
+}
+
+// fuzzyMatcher highlights the words of a line close to a word of q.
+func fuzzyMatcher(q string) snippet.Matcher {
+	return func(line string) []snippet.Match {
+		var matches []snippet.Match
+		for _, span := range fuzzy.Spans(q, line) {
+			matches = append(matches, snippet.Match{Start: span.Start, End: span.End})
+		}
+		return matches
+	}
+}
+
 func init() {
 	if noteMode {
-		rootCmd.AddCommand(searchCmd)
+		searchCmd.Flags().StringSliceVar(&searchTags, "tag", nil, "Only show notes with this tag (repeatable)")
+		searchCmd.Flags().StringSliceVar(&searchNotTags, "not-tag", nil, "Hide notes with this tag (repeatable)")
+		searchCmd.Flags().IntVarP(&searchContext, "context", "C", 1, "Lines of context to show around each match")
+		searchCmd.Flags().BoolVarP(&searchRegex, "regex", "e", false, "Treat the query as a regular expression (RE2 syntax)")
+		searchCmd.Flags().BoolVar(&searchFuzzy, "fuzzy", false, "Match words with a few typos")
+		searchCmd.Flags().StringVar(&searchScope, "scope", string(notes.ScopeBoth), "What --regex matches: name, content or both")
+		searchCmd.Flags().StringVar(&searchFormat, "format", "",
+			"Print each note with a Go template, or a named template ("+templateNames(noteTemplates())+"), instead of snippets")
+		searchCmd.MarkFlagsMutuallyExclusive("regex", "fuzzy")
+		rootCmd.AddCommand(supportsOutput(searchCmd))
 	}
 }
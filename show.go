@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var showRevision int
+
+var showCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show a note",
+	Long:  "Show a note. Use --rev to show an earlier revision from the note's history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task show' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		var note *notes.Note
+		if showRevision > 0 {
+			note, err = nm.GetRevision(id, showRevision)
+			if err != nil {
+				return exitWith(cmd, exitNotFound, "Revision %d of note %s not found", showRevision, id)
+			}
+		} else {
+			note, err = nm.GetNote(id)
+			if err != nil {
+				return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+			}
+		}
+
+		if machineOutput() {
+			return output.One(os.Stdout, outputFormat, *note, noteOutputColumns)
+		}
+
+		fmt.Printf("ID: %s\n", note.ID)
+		if showRevision > 0 {
+			fmt.Printf("Revision: %d\n", showRevision)
+		}
+		fmt.Printf("Name: %s\n", note.Name)
+		if len(note.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(note.Tags, ", "))
+		}
+		fmt.Printf("Created: %s\n", note.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Updated: %s\n", note.UpdatedAt.Format("2006-01-02 15:04:05"))
+		if tm, err := tasks.NewTaskManager(); err == nil {
+			linked, err := tm.ListTasks(tasks.Filter{NoteID: note.ID})
+			if err != nil {
+				return err
+			}
+			for _, task := range linked {
+				fmt.Printf("Task: %s | %s | [%s]\n", task.ID, task.Name, task.Status)
+			}
+		}
+		fmt.Println()
+		fmt.Println(note.Content)
+		return nil
+	},
+}
+
+func init() {
+	if noteMode {
+		showCmd.Flags().IntVarP(&showRevision, "rev", "r", 0, "Show revision N from the note's history")
+		rootCmd.AddCommand(supportsOutput(showCmd))
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wltechblog/notes/internal/snippet"
+)
+
+// maxSnippets is the most snippets shown under each search result.
+const maxSnippets = 3
+
+// useColor reports whether output may contain ANSI escapes: stdout is a
+// terminal and NO_COLOR is not set.
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printSnippets prints the lines of content around what match finds,
+// indented under the result line they belong to.
+func printSnippets(content string, match snippet.Matcher, context int) {
+	snippets := snippet.Find(content, match, context, maxSnippets)
+	fmt.Print(snippet.Format(snippets, "    ", useColor()))
+}
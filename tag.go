@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/tags"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag [id] [add|remove] [tag]...",
+	Short: "Add or remove tags on a note",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task tag' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id, action := args[0], args[1]
+		tagList, err := parseTagArgs(args[2:])
+		if err != nil {
+			return err
+		}
+
+		var note *notes.Note
+		switch action {
+		case "add":
+			note, err = nm.AddTags(id, tagList...)
+		case "remove", "rm":
+			note, err = nm.RemoveTags(id, tagList...)
+		default:
+			return fmt.Errorf("invalid action: %s (must be: add or remove)", action)
+		}
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", id)
+		}
+
+		fmt.Printf("Note %s tags: %s\n", id, strings.Join(note.Tags, ", "))
+		return nil
+	},
+}
+
+func parseTagArgs(args []string) ([]string, error) {
+	var tagList []string
+	for _, arg := range args {
+		tag := tags.Normalize(arg)
+		if err := tags.Validate(tag); err != nil {
+			return nil, err
+		}
+		tagList = append(tagList, tag)
+	}
+	return tagList, nil
+}
+
+func formatTagList(tagList []string) string {
+	if len(tagList) == 0 {
+		return ""
+	}
+	return " | +" + strings.Join(tagList, " +")
+}
+
+func init() {
+	if noteMode {
+		rootCmd.AddCommand(tagCmd)
+	}
+}
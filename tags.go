@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List tags with the number of notes using each",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task tags' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		counts, err := nm.TagCounts()
+		if err != nil {
+			return err
+		}
+
+		printTagCounts(counts)
+		return nil
+	},
+}
+
+func printTagCounts(counts map[string]int) {
+	if len(counts) == 0 {
+		fmt.Println("No tags found")
+		return
+	}
+
+	tagList := make([]string, 0, len(counts))
+	for tag := range counts {
+		tagList = append(tagList, tag)
+	}
+	sort.Strings(tagList)
+
+	for _, tag := range tagList {
+		fmt.Printf("%s | %d\n", tag, counts[tag])
+	}
+}
+
+func init() {
+	if noteMode {
+		rootCmd.AddCommand(tagsCmd)
+	}
+}
@@ -2,21 +2,33 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
 	"github.com/wltechblog/notes/internal/tasks"
 )
 
+var (
+	taskDeleteKeepNote   bool
+	taskDeleteDeleteNote bool
+)
+
 var taskDeleteCmd = &cobra.Command{
 	Use:     "delete [id]",
 	Aliases: []string{"del", "rm"},
 	Short:   "Delete a task",
-	Args:    cobra.ExactArgs(1),
+	Long: "Delete a task. If it is linked to a note you are asked whether to delete the note too; " +
+		"--keep-note or --delete-note answer in advance. Without a terminal the note is kept",
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !taskMode {
 			return fmt.Errorf("this command is only available for tasks, use 'note delete' instead")
 		}
+		if taskDeleteKeepNote && taskDeleteDeleteNote {
+			return fmt.Errorf("--keep-note and --delete-note cannot be used together")
+		}
 		tm, err := tasks.NewTaskManager()
 		if err != nil {
 			return err
@@ -25,8 +37,7 @@ var taskDeleteCmd = &cobra.Command{
 		id := args[0]
 		task, err := tm.GetTask(id)
 		if err != nil {
-			fmt.Printf("Task not found: %s\n", id)
-			return nil
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
 		}
 
 		nm, err := notes.NewNoteManager()
@@ -34,24 +45,56 @@ var taskDeleteCmd = &cobra.Command{
 			return err
 		}
 
+		var (
+			deleted     []deletion
+			noteTrashed bool
+		)
 		if task.NoteID != "" {
-			if err := nm.DeleteNote(task.NoteID); err != nil {
-				fmt.Printf("Failed to delete note: %v\n", err)
+			if note, err := nm.GetNote(task.NoteID); err == nil && deleteLinkedNote(tm, task, note) {
+				if err := nm.DeleteNote(task.NoteID); err != nil {
+					notice("Failed to delete note: %v\n", err)
+				} else {
+					deleted = append(deleted, deletion{Type: "note", ID: note.ID, Name: note.Name})
+					noteTrashed = true
+					notice("Note deleted: %s\n", task.NoteID)
+				}
 			}
 		}
 
-		if err := tm.DeleteTask(id); err != nil {
-			fmt.Printf("Failed to delete task: %v\n", err)
-			return nil
+		if err := tm.DeleteTask(id, noteTrashed); err != nil {
+			return err
 		}
+		deleted = append(deleted, deletion{Type: "task", ID: id, Name: task.Name})
 
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, deleted, deletionColumns)
+		}
 		fmt.Printf("Task deleted: %s\n", id)
 		return nil
 	},
 }
 
+// deleteLinkedNote decides whether the note linked to task goes with it. A
+// note that other tasks still reference is always kept.
+func deleteLinkedNote(tm *tasks.TaskManager, task *tasks.Task, note *notes.Note) bool {
+	linked, err := tm.ListTasks(tasks.Filter{NoteID: note.ID})
+	if err != nil || len(linked) > 1 {
+		return false
+	}
+
+	switch {
+	case taskDeleteKeepNote:
+		return false
+	case taskDeleteDeleteNote:
+		return true
+	}
+	return confirm(fmt.Sprintf("Task %s is linked to note %s (%s). Delete the note too?", task.ID, note.ID, note.Name), false)
+}
+
 func init() {
 	if taskMode {
-		rootCmd.AddCommand(taskDeleteCmd)
+		taskDeleteCmd.Flags().BoolVar(&taskDeleteKeepNote, "keep-note", false, "Keep the linked note without asking")
+		taskDeleteCmd.Flags().BoolVar(&taskDeleteDeleteNote, "delete-note", false, "Move the linked note to the trash without asking")
+		rootCmd.AddCommand(supportsOutput(taskDeleteCmd))
 	}
 }
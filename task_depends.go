@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskDependsRemove bool
+
+var taskDependsCmd = &cobra.Command{
+	Use:   "depends [id] [other-id...]",
+	Short: "Record that a task is blocked by other tasks",
+	Long: "Record that a task cannot start until the given tasks are done. " +
+		"Use --remove to drop dependencies. Dependencies that would form a cycle are rejected",
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id, deps := args[0], args[1:]
+
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		var task *tasks.Task
+		if taskDependsRemove {
+			task, err = tm.RemoveDependencies(id, deps...)
+		} else {
+			task, err = tm.AddDependencies(id, deps...)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(task.DependsOn) == 0 {
+			fmt.Printf("Task %s has no dependencies\n", id)
+		} else {
+			fmt.Printf("Task %s depends on: %s\n", id, strings.Join(task.DependsOn, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		taskDependsCmd.Flags().BoolVar(&taskDependsRemove, "remove", false, "Remove the given dependencies instead of adding them")
+		rootCmd.AddCommand(taskDependsCmd)
+	}
+}
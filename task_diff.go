@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/diff"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskDiffCmd = &cobra.Command{
+	Use:   "diff [id] [revA] [revB]",
+	Short: "Show changes between revisions of a task",
+	Long: "Show changes between revisions of a task. With no revisions, compares the latest " +
+		"revision with the current task; with one, compares that revision with the current task",
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note diff' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		current, err := tm.GetTask(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		var revA int
+		if len(args) > 1 {
+			if revA, err = parseRevision(args[1]); err != nil {
+				return err
+			}
+		} else {
+			revisions, err := tm.History(id)
+			if err != nil {
+				return err
+			}
+			if len(revisions) == 0 {
+				fmt.Printf("Task %s has no earlier revisions\n", id)
+				return nil
+			}
+			revA = revisions[len(revisions)-1].Number
+		}
+
+		a, err := tm.GetRevision(id, revA)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Revision %d of task %s not found", revA, id)
+		}
+		aLabel := fmt.Sprintf("task %s revision %d", id, revA)
+
+		b, bLabel := current, fmt.Sprintf("task %s (current)", id)
+		if len(args) > 2 {
+			revB, err := parseRevision(args[2])
+			if err != nil {
+				return err
+			}
+			if b, err = tm.GetRevision(id, revB); err != nil {
+				return exitWith(cmd, exitNotFound, "Revision %d of task %s not found", revB, id)
+			}
+			bLabel = fmt.Sprintf("task %s revision %d", id, revB)
+		}
+
+		out := diff.Unified(taskDiffText(a), taskDiffText(b), aLabel, bLabel, 3)
+		if out == "" {
+			fmt.Println("No differences")
+			return nil
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+func taskDiffText(task *tasks.Task) string {
+	text := fmt.Sprintf("Name: %s\nStatus: %s\nNoteID: %s\n", task.Name, task.Status, task.NoteID)
+	if len(task.Tags) > 0 {
+		text += fmt.Sprintf("Tags: %s\n", strings.Join(task.Tags, ", "))
+	}
+	if task.ParentID != "" {
+		text += fmt.Sprintf("Parent: %s\n", task.ParentID)
+	}
+	if len(task.DependsOn) > 0 {
+		text += fmt.Sprintf("Depends: %s\n", strings.Join(task.DependsOn, ", "))
+	}
+	if task.Recur != "" {
+		text += fmt.Sprintf("Recur: %s\n", task.Recur)
+	}
+	if task.Project != "" {
+		text += fmt.Sprintf("Project: %s\n", task.Project)
+	}
+	if task.Priority != tasks.PriorityNone {
+		text += fmt.Sprintf("Priority: %s\n", task.Priority)
+	}
+	if task.DueAt != nil {
+		text += fmt.Sprintf("Due: %s\n", formatDate(*task.DueAt))
+	}
+	if task.ScheduledAt != nil {
+		text += fmt.Sprintf("Scheduled: %s\n", formatDate(*task.ScheduledAt))
+	}
+	return text + "\n" + task.Content
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskDiffCmd)
+	}
+}
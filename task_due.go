@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskDueCmd = &cobra.Command{
+	Use:   "due [id] [date]",
+	Short: "Set or clear a task's due date",
+	Long:  "Set a task's due date, or clear it by passing 'none'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		due, err := parseOptionalDate(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.SetDue(id, due); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if due == nil {
+			fmt.Printf("Task %s due date cleared\n", id)
+		} else {
+			fmt.Printf("Task %s due: %s\n", id, formatDate(*due))
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskDueCmd)
+	}
+}
@@ -23,8 +23,7 @@ var taskEditCmd = &cobra.Command{
 		id := args[0]
 		task, err := tm.GetTask(id)
 		if err != nil {
-			fmt.Printf("Task not found: %s\n", id)
-			return nil
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
 		}
 
 		if err := tm.EditInEditor(task); err != nil {
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/dateparse"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskEstimateCmd = &cobra.Command{
+	Use:   "estimate [id] [duration]",
+	Short: "Set or clear a task's time estimate",
+	Long:  "Set how long a task is expected to take (e.g. 2h, 1d), or clear the estimate by passing 'none'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		estimate, err := parseEstimate(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+		if _, err := tm.SetEstimate(id, estimate); err != nil {
+			return err
+		}
+
+		if estimate == 0 {
+			fmt.Printf("Task %s estimate cleared\n", id)
+		} else {
+			fmt.Printf("Task %s estimate: %s\n", id, formatDuration(estimate))
+		}
+		return nil
+	},
+}
+
+// parseEstimate is dateparse.ParseDuration where "none" clears the estimate.
+func parseEstimate(s string) (time.Duration, error) {
+	if s == "none" {
+		return 0, nil
+	}
+	return dateparse.ParseDuration(s)
+}
+
+// formatTracked renders tracked time against the estimate, if there is one.
+func formatTracked(task *tasks.Task, now time.Time) string {
+	tracked := task.Tracked(now)
+	if task.Estimate == 0 {
+		return formatDuration(tracked)
+	}
+	return fmt.Sprintf("%s of %s (%d%%)", formatDuration(tracked), formatDuration(task.Estimate),
+		int(tracked*100/task.Estimate))
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskEstimateCmd)
+	}
+}
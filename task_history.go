@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskHistoryCmd = &cobra.Command{
+	Use:   "history [id]",
+	Short: "List the saved revisions of a task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note history' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		task, err := tm.GetTask(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		revisions, err := tm.History(id)
+		if err != nil {
+			return err
+		}
+
+		for _, rev := range revisions {
+			fmt.Printf("%d | %s | [%s] | Updated: %s\n",
+				rev.Number,
+				rev.Task.Name,
+				rev.Task.Status,
+				rev.Task.UpdatedAt.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Printf("current | %s | [%s] | Updated: %s\n",
+			task.Name,
+			task.Status,
+			task.UpdatedAt.Format("2006-01-02 15:04:05"))
+
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskHistoryCmd)
+	}
+}
@@ -2,20 +2,42 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/output"
 	"github.com/wltechblog/notes/internal/tasks"
+	"github.com/wltechblog/notes/internal/templates"
 )
 
 var (
-	statusFilter string
+	statusFilter    string
+	overdueFilter   bool
+	dueTodayFilter  bool
+	dueBeforeFilter string
+	taskSortKey     string
+	taskListTags    []string
+	taskListNotTags []string
+	projectFilter   string
+	taskTree        bool
+	readyFilter     bool
+	blockedFilter   bool
+	taskListQuery   string
+	taskListFormat  string
 )
 
 var taskListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List all tasks",
-	Long:    "List all tasks. Use --status flag to filter by open, completed, or abandoned",
+	Long: "List all tasks. Use --status to filter by status " +
+		"and --overdue, --due-today or --due-before to filter by due date. " +
+		"Use --sort urgency to put the most pressing tasks first. " +
+		"--query filters with the query language, e.g. 'status:open AND (tag:work OR due<eow)'. " +
+		"--ready shows open tasks with no open dependencies and --blocked shows what each waiting task depends on. " +
+		"--format prints each task with a template such as '{{.ID}}\\t{{.Name}}\\t{{.Status}}' (see README)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !taskMode {
 			return fmt.Errorf("this command is only available for tasks, use 'note list' instead")
@@ -25,13 +47,44 @@ var taskListCmd = &cobra.Command{
 			return err
 		}
 
-		var filter tasks.Status
+		var filter tasks.Filter
 		if statusFilter != "" {
-			filter = tasks.Status(statusFilter)
-			switch filter {
-			case tasks.StatusOpen, tasks.StatusCompleted, tasks.StatusAbandoned:
-			default:
-				return fmt.Errorf("invalid status: %s (must be: open, completed, or abandoned)", statusFilter)
+			filter.Status = tasks.Status(statusFilter)
+			if workflow := tasks.CurrentWorkflow(); !workflow.Valid(filter.Status) {
+				return fmt.Errorf("invalid status: %s (must be one of: %s)", statusFilter, workflow.Describe())
+			}
+		}
+
+		now := time.Now()
+		filter.Overdue = overdueFilter
+		filter.Tags = taskListTags
+		filter.NotTags = taskListNotTags
+		filter.Project = projectFilter
+		if filter.Query, err = tasks.ParseQuery(taskListQuery); err != nil {
+			return err
+		}
+		filter.Ready = readyFilter
+		filter.Blocked = blockedFilter
+		if readyFilter && blockedFilter {
+			return fmt.Errorf("--ready and --blocked cannot be used together")
+		}
+		if taskTree && machineOutput() {
+			return fmt.Errorf("--tree cannot be used with --output %s (records carry parent_id instead)", outputFormat)
+		}
+		if taskTree && taskListFormat != "" {
+			return fmt.Errorf("--format cannot be used with --tree")
+		}
+		if dueTodayFilter {
+			filter.DueAfter = startOfDay(now)
+			filter.DueBefore = filter.DueAfter.AddDate(0, 0, 1)
+		}
+		if dueBeforeFilter != "" {
+			dueBefore, err := parseDate(dueBeforeFilter)
+			if err != nil {
+				return err
+			}
+			if filter.DueBefore.IsZero() || dueBefore.Before(filter.DueBefore) {
+				filter.DueBefore = dueBefore
 			}
 		}
 
@@ -40,32 +93,126 @@ var taskListCmd = &cobra.Command{
 			return err
 		}
 
+		if err := tasks.SortTasks(taskList, taskSortKey, now); err != nil {
+			return err
+		}
+
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, taskList, taskOutputColumns)
+		}
+
+		if taskListFormat != "" {
+			tmpl, err := loadTemplate(taskListFormat, taskTemplates())
+			if err != nil {
+				return err
+			}
+			for _, task := range taskList {
+				if err := printTemplate(tmpl, newTaskTemplateData(&task, 0, now)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
 		if len(taskList) == 0 {
 			fmt.Println("No tasks found")
 			return nil
 		}
 
+		if taskTree {
+			for _, root := range tasks.BuildTree(taskList) {
+				printTaskTree(root, 0, now)
+			}
+			return nil
+		}
+
+		var blockers map[string][]string
+		if blockedFilter {
+			blockers, err = tm.OpenBlockers()
+			if err != nil {
+				return err
+			}
+		}
+
 		for _, task := range taskList {
-			contentPreview := task.Content
-			if len(contentPreview) > 30 {
-				contentPreview = contentPreview[:30] + "..."
+			extra := ""
+			if deps := blockers[task.ID]; len(deps) > 0 {
+				extra = fmt.Sprintf(" | Blocked by: %s", strings.Join(deps, ", "))
 			}
-			fmt.Printf("%s | %s | [%s] | %s | Created: %s | Updated: %s\n",
-				task.ID,
-				task.Name,
-				task.Status,
-				contentPreview,
-				task.CreatedAt.Format("2006-01-02 15:04:05"),
-				task.UpdatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Println(formatTaskLine(&task, extra, now))
 		}
 
 		return nil
 	},
 }
 
+func printTaskTree(node *tasks.TreeNode, depth int, now time.Time) {
+	extra := ""
+	if len(node.Children) > 0 {
+		done, total := node.Progress()
+		percent := 100
+		if total > 0 {
+			percent = done * 100 / total
+		}
+		extra = fmt.Sprintf(" | Subtasks: %d/%d (%d%%)", done, total, percent)
+	}
+	fmt.Println(strings.Repeat("  ", depth) + formatTaskLine(&node.Task, extra, now))
+
+	for _, child := range node.Children {
+		printTaskTree(child, depth+1, now)
+	}
+}
+
+func formatTaskLine(task *tasks.Task, extra string, now time.Time) string {
+	contentPreview := templates.Truncate(previewLength, task.Content)
+	details := formatTagList(task.Tags)
+	if task.Project != "" {
+		details += fmt.Sprintf(" | Project: %s", task.Project)
+	}
+	if task.Priority != tasks.PriorityNone {
+		details += fmt.Sprintf(" | Priority: %s", task.Priority)
+	}
+	if task.DueAt != nil {
+		details += fmt.Sprintf(" | Due: %s", formatDate(*task.DueAt))
+		if task.IsOverdue(now) {
+			details += " (OVERDUE)"
+		}
+	}
+	if task.Recur != "" {
+		details += fmt.Sprintf(" | Recur: %s", task.Recur)
+	}
+	if taskSortKey == "urgency" {
+		details += fmt.Sprintf(" | Urgency: %.2f", task.Urgency(now))
+	}
+	return fmt.Sprintf("%s | %s | [%s] | %s%s%s | Created: %s | Updated: %s",
+		task.ID,
+		task.Name,
+		task.Status,
+		contentPreview,
+		details,
+		extra,
+		task.CreatedAt.Format("2006-01-02 15:04:05"),
+		task.UpdatedAt.Format("2006-01-02 15:04:05"))
+}
+
 func init() {
 	if taskMode {
-		taskListCmd.Flags().StringVarP(&statusFilter, "status", "s", "", "Filter by status (open, completed, abandoned)")
-		rootCmd.AddCommand(taskListCmd)
+		taskListCmd.Flags().StringVarP(&statusFilter, "status", "s", "",
+			"Filter by status ("+strings.Join(statusNames(tasks.CurrentWorkflow().Names()), ", ")+")")
+		taskListCmd.RegisterFlagCompletionFunc("status", completeStatuses)
+		taskListCmd.Flags().BoolVar(&overdueFilter, "overdue", false, "Only show open tasks past their due date")
+		taskListCmd.Flags().BoolVar(&dueTodayFilter, "due-today", false, "Only show tasks due today")
+		taskListCmd.Flags().StringVar(&dueBeforeFilter, "due-before", "", "Only show tasks due before this date")
+		taskListCmd.Flags().StringSliceVar(&taskListTags, "tag", nil, "Only show tasks with this tag (repeatable)")
+		taskListCmd.Flags().StringSliceVar(&taskListNotTags, "not-tag", nil, "Hide tasks with this tag (repeatable)")
+		taskListCmd.Flags().StringVar(&projectFilter, "project", "", "Only show tasks in this project or its sub-projects")
+		taskListCmd.Flags().BoolVar(&taskTree, "tree", false, "Show subtasks nested under their parents with completion progress")
+		taskListCmd.Flags().StringVarP(&taskListQuery, "query", "q", "", "Only show tasks matching this query")
+		taskListCmd.Flags().BoolVar(&readyFilter, "ready", false, "Only show open tasks whose dependencies are all done")
+		taskListCmd.Flags().BoolVar(&blockedFilter, "blocked", false, "Only show tasks waiting on open dependencies")
+		taskListCmd.Flags().StringVar(&taskSortKey, "sort", "id", "Sort by "+strings.Join(tasks.SortKeys, ", "))
+		taskListCmd.Flags().StringVar(&taskListFormat, "format", "",
+			"Print each task with a Go template, or a named template ("+templateNames(taskTemplates())+")")
+		rootCmd.AddCommand(supportsOutput(taskListCmd))
 	}
 }
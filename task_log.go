@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/dateparse"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskLogCmd = &cobra.Command{
+	Use:   "log [id] [duration]",
+	Short: "Log time spent on a task",
+	Long:  "Record time spent on a task without a timer, e.g. 'task log 3 45m' or 'task log 3 1h30m'. The interval is taken to end now",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		d, err := dateparse.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		task, err := tm.LogTime(id, d)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Logged %s on task %s, %s in total\n", formatDuration(d), id, formatDuration(task.Tracked(time.Now())))
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskLogCmd)
+	}
+}
@@ -2,16 +2,30 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/tags"
 	"github.com/wltechblog/notes/internal/tasks"
 )
 
+var (
+	taskNewDue       string
+	taskNewScheduled string
+	taskNewPriority  string
+	taskNewProject   string
+	taskNewParent    string
+	taskNewRecur     string
+	taskNewEstimate  string
+)
+
 var taskNewCmd = &cobra.Command{
-	Use:     "new [name]",
+	Use:     "new [name] [+tag]...",
 	Aliases: []string{"create"},
 	Short:   "Create a new task",
-	Args:    cobra.MaximumNArgs(1),
+	Long:    "Create a new task. Arguments starting with '+' are added as tags; the rest form the name",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !taskMode {
 			return fmt.Errorf("this command is only available for tasks, use 'note new' instead")
@@ -21,13 +35,42 @@ var taskNewCmd = &cobra.Command{
 			return err
 		}
 
-		name := ""
-		if len(args) > 0 {
-			name = args[0]
+		tagList, nameArgs, err := tags.Split(args)
+		if err != nil {
+			return err
 		}
+		name := strings.Join(nameArgs, " ")
 
-		task, err := tm.CreateTask(name, "")
-		if err != nil {
+		task := &tasks.Task{Name: name, Tags: tagList, Project: taskNewProject, ParentID: taskNewParent}
+		if task.Priority, err = tasks.ParsePriority(taskNewPriority); err != nil {
+			return err
+		}
+		if rule, err := tasks.ParseRecurrence(taskNewRecur); err != nil {
+			return err
+		} else if rule != nil {
+			task.Recur = rule.String()
+		}
+		if taskNewEstimate != "" {
+			if task.Estimate, err = parseEstimate(taskNewEstimate); err != nil {
+				return err
+			}
+		}
+		if taskNewDue != "" {
+			due, err := parseDate(taskNewDue)
+			if err != nil {
+				return err
+			}
+			task.DueAt = &due
+		}
+		if taskNewScheduled != "" {
+			scheduled, err := parseDate(taskNewScheduled)
+			if err != nil {
+				return err
+			}
+			task.ScheduledAt = &scheduled
+		}
+
+		if err := tm.Create(task); err != nil {
 			return err
 		}
 
@@ -36,13 +79,20 @@ var taskNewCmd = &cobra.Command{
 		}
 
 		if task.Content == "" {
-			if err := tm.DeleteTask(task.ID); err != nil {
+			if err := tm.PurgeTask(task.ID); err != nil {
 				return err
 			}
-			fmt.Println("Task not saved (empty content)")
-			return nil
+			return exitWith(cmd, exitError, "Task not saved (empty content)")
 		}
 
+		if machineOutput() {
+			// Read the task back so timestamps match what was stored.
+			saved, err := tm.GetTask(task.ID)
+			if err != nil {
+				return err
+			}
+			return output.One(os.Stdout, outputFormat, *saved, taskOutputColumns)
+		}
 		fmt.Printf("Task created: %s\n", task.ID)
 		return nil
 	},
@@ -50,6 +100,13 @@ var taskNewCmd = &cobra.Command{
 
 func init() {
 	if taskMode {
-		rootCmd.AddCommand(taskNewCmd)
+		taskNewCmd.Flags().StringVar(&taskNewDue, "due", "", "Due date")
+		taskNewCmd.Flags().StringVar(&taskNewScheduled, "scheduled", "", "Date to start working on the task")
+		taskNewCmd.Flags().StringVarP(&taskNewPriority, "priority", "p", "", "Priority (H, M, L)")
+		taskNewCmd.Flags().StringVar(&taskNewParent, "parent", "", "Make the new task a subtask of this task ID")
+		taskNewCmd.Flags().StringVar(&taskNewProject, "project", "", "Project, with dots for sub-projects (e.g. work.backend)")
+		taskNewCmd.Flags().StringVar(&taskNewRecur, "recur", "", "Repeat the task: daily, weekly[:mon,thu], monthly[:N] or every:Nd")
+		taskNewCmd.Flags().StringVar(&taskNewEstimate, "estimate", "", "Expected time to complete (e.g. 2h, 1d)")
+		rootCmd.AddCommand(supportsOutput(taskNewCmd))
 	}
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskNoteCmd = &cobra.Command{
+	Use:   "note [id]",
+	Short: "Open the note attached to a task",
+	Long:  "Open the task's linked note in the editor, or create one named after the task and link it if there is none",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		task, err := tm.GetTask(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if task.NoteID != "" {
+			if note, err := nm.GetNote(task.NoteID); err == nil {
+				if err := nm.EditInEditor(note); err != nil {
+					return err
+				}
+				fmt.Printf("Note updated: %s\n", note.ID)
+				return syncChecklistToTasks(note)
+			}
+			fmt.Printf("Linked note %s not found, creating a new one\n", task.NoteID)
+		}
+
+		note := &notes.Note{Name: task.Name, Tags: task.Tags}
+		if err := nm.Create(note); err != nil {
+			return err
+		}
+
+		if err := nm.EditInEditor(note); err != nil {
+			return err
+		}
+
+		if note.Content == "" {
+			if err := nm.PurgeNote(note.ID); err != nil {
+				return err
+			}
+			fmt.Println("Note not saved (empty content)")
+			return nil
+		}
+
+		if _, err := tm.SetNote(id, note.ID, 0); err != nil {
+			return err
+		}
+
+		fmt.Printf("Note created: %s (linked to task %s)\n", note.ID, id)
+		return nil
+	},
+}
+
+var taskLinkCmd = &cobra.Command{
+	Use:   "link [task-id] [note-id]",
+	Short: "Link a task to an existing note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id, noteID := args[0], args[1]
+		if _, err := nm.GetNote(noteID); err != nil {
+			return exitWith(cmd, exitNotFound, "Note not found: %s", noteID)
+		}
+
+		if _, err := tm.SetNote(id, noteID, 0); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		fmt.Printf("Task %s linked to note %s\n", id, noteID)
+		return nil
+	},
+}
+
+var taskUnlinkCmd = &cobra.Command{
+	Use:   "unlink [task-id]",
+	Short: "Remove a task's link to its note",
+	Long:  "Remove a task's link to its note. The note itself is kept",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		task, err := tm.GetTask(id)
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+		if task.NoteID == "" {
+			fmt.Printf("Task %s is not linked to a note\n", id)
+			return nil
+		}
+
+		if _, err := tm.SetNote(id, "", 0); err != nil {
+			return err
+		}
+
+		fmt.Printf("Task %s unlinked from note %s\n", id, task.NoteID)
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskNoteCmd)
+		rootCmd.AddCommand(taskLinkCmd)
+		rootCmd.AddCommand(taskUnlinkCmd)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskParentCmd = &cobra.Command{
+	Use:   "parent [id] [parent-id]",
+	Short: "Make a task a subtask of another, or detach it",
+	Long:  "Make a task a subtask of another task, or detach it from its parent by passing 'none'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id, parentID := args[0], args[1]
+		if parentID == "none" {
+			parentID = ""
+		}
+
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if _, err := tm.SetParent(id, parentID); err != nil {
+			return err
+		}
+
+		if parentID == "" {
+			fmt.Printf("Task %s detached from its parent\n", id)
+		} else {
+			fmt.Printf("Task %s is now a subtask of %s\n", id, parentID)
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskParentCmd)
+	}
+}
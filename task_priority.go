@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskPriorityCmd = &cobra.Command{
+	Use:   "priority [id] [priority]",
+	Short: "Set or clear a task's priority (H, M, L)",
+	Long:  "Set a task's priority to H (high), M (medium) or L (low), or clear it by passing 'none'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		priority, err := tasks.ParsePriority(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.SetPriority(id, priority); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if priority == tasks.PriorityNone {
+			fmt.Printf("Task %s priority cleared\n", id)
+		} else {
+			fmt.Printf("Task %s priority: %s\n", id, priority)
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskPriorityCmd)
+	}
+}
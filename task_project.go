@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskProjectCmd = &cobra.Command{
+	Use:   "project [id] [project]",
+	Short: "Set or clear a task's project",
+	Long:  "Set a task's project using dotted names for sub-projects (e.g. work.backend.auth), or clear it by passing 'none'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id, project := args[0], args[1]
+		if project == "none" {
+			project = ""
+		}
+		if err := tasks.ValidateProject(project); err != nil {
+			return err
+		}
+
+		if _, err := tm.SetProject(id, project); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if project == "" {
+			fmt.Printf("Task %s project cleared\n", id)
+		} else {
+			fmt.Printf("Task %s project: %s\n", id, project)
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskProjectCmd)
+	}
+}
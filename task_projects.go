@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskProjectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Summarize open and completed tasks per project",
+	Long:  "Summarize open and completed tasks per project. Counts for sub-projects are included in their parents",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		taskList, err := tm.ListTasks(tasks.Filter{})
+		if err != nil {
+			return err
+		}
+
+		summaries := tasks.SummarizeProjects(taskList)
+		if len(summaries) == 0 {
+			fmt.Println("No projects found")
+			return nil
+		}
+
+		for _, summary := range summaries {
+			fmt.Printf("%s | Open: %d | Completed: %d\n",
+				summary.Name,
+				summary.Open,
+				summary.Completed)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskProjectsCmd)
+	}
+}
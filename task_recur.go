@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskRecurCmd = &cobra.Command{
+	Use:   "recur [id] [rule]",
+	Short: "Set or clear a task's recurrence rule",
+	Long: "Make a task repeat. Rules: daily, weekly, weekly:mon,thu, monthly, monthly:15, or every:3d " +
+		"(counted from completion). Completing the task creates the next instance with a shifted due date. " +
+		"Pass 'none' to stop the series",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		rule, err := tasks.ParseRecurrence(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.SetRecurrence(id, rule); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if rule == nil {
+			fmt.Printf("Task %s no longer recurs\n", id)
+		} else {
+			fmt.Printf("Task %s recurs: %s\n", id, rule)
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskRecurCmd)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskReindexStem bool
+
+var taskReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the search index",
+	Long: "Rebuild the search index from every task. The index is kept up to date as tasks change; " +
+		"rebuild it after editing task files outside this tool, or to turn stemming on or off",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note reindex' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		count, err := tm.Reindex(taskReindexStem)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Indexed %d tasks\n", count)
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		taskReindexCmd.Flags().BoolVar(&taskReindexStem, "stem", false, "Index words by their stems, so 'deploying' matches 'deployed'")
+		rootCmd.AddCommand(taskReindexCmd)
+	}
+}
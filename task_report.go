@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var (
+	taskReportStatus string
+	taskReportSince  string
+	taskReportUntil  string
+)
+
+var taskReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "List status changes over a period",
+	Long: "List recorded status changes, oldest first. Use --status to only show changes into one status " +
+		"and --since/--until to limit the period, e.g. 'task report --status completed --since \"last week\" --until sow'",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		var filter tasks.ReportFilter
+		if taskReportStatus != "" {
+			filter.Status = tasks.Status(taskReportStatus)
+			if workflow := tasks.CurrentWorkflow(); !workflow.Valid(filter.Status) {
+				return fmt.Errorf("invalid status: %s (must be one of: %s)", taskReportStatus, workflow.Describe())
+			}
+		}
+		if taskReportSince != "" {
+			if filter.Since, err = parseDate(taskReportSince); err != nil {
+				return err
+			}
+		}
+		if taskReportUntil != "" {
+			if filter.Until, err = parseDate(taskReportUntil); err != nil {
+				return err
+			}
+		}
+
+		events, err := tm.Report(filter)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No status changes found")
+			return nil
+		}
+
+		for _, event := range events {
+			fmt.Printf("%s | %s | %s\n", event.Task.ID, event.Task.Name, formatTransition(event.Transition))
+		}
+		return nil
+	},
+}
+
+func formatTransition(tr tasks.Transition) string {
+	line := fmt.Sprintf("%s | %s -> %s", tr.At.Format("2006-01-02 15:04:05"), tr.From, tr.To)
+	if tr.Comment != "" {
+		line += " | " + tr.Comment
+	}
+	return line
+}
+
+func init() {
+	if taskMode {
+		taskReportCmd.Flags().StringVarP(&taskReportStatus, "status", "s", "", "Only show changes into this status")
+		taskReportCmd.Flags().StringVar(&taskReportSince, "since", "", "Only show changes at or after this date")
+		taskReportCmd.Flags().StringVar(&taskReportUntil, "until", "", "Only show changes before this date")
+		taskReportCmd.RegisterFlagCompletionFunc("status", completeStatuses)
+		rootCmd.AddCommand(taskReportCmd)
+	}
+}
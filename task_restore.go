@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskRestoreCmd = &cobra.Command{
+	Use:   "restore [id] [rev]",
+	Short: "Restore a task to an earlier revision",
+	Long:  "Restore a task to an earlier revision. The current version is kept in the task's history",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note restore' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		rev, err := parseRevision(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+		if _, err := tm.GetRevision(id, rev); err != nil {
+			return exitWith(cmd, exitNotFound, "Revision %d of task %s not found", rev, id)
+		}
+
+		if _, err := tm.RestoreRevision(id, rev); err != nil {
+			return exitWith(cmd, exitError, "Failed to restore task: %v", err)
+		}
+
+		fmt.Printf("Task %s restored to revision %d\n", id, rev)
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskRestoreCmd)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskScheduleCmd = &cobra.Command{
+	Use:   "schedule [id] [date]",
+	Short: "Set or clear the date to start working on a task",
+	Long:  "Set the date to start working on a task, or clear it by passing 'none'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		scheduled, err := parseOptionalDate(args[1])
+		if err != nil {
+			return err
+		}
+
+		if _, err := tm.SetScheduled(id, scheduled); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		if scheduled == nil {
+			fmt.Printf("Task %s scheduled date cleared\n", id)
+		} else {
+			fmt.Printf("Task %s scheduled: %s\n", id, formatDate(*scheduled))
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskScheduleCmd)
+	}
+}
@@ -2,43 +2,121 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/snippet"
 	"github.com/wltechblog/notes/internal/tasks"
 )
 
+var (
+	taskSearchTags    []string
+	taskSearchNotTags []string
+	taskSearchContext int
+	taskSearchRegex   bool
+	taskSearchFuzzy   bool
+	taskSearchScope   string
+	taskSearchFormat  string
+)
+
 var taskSearchCmd = &cobra.Command{
-	Use:   "search [keyword]",
-	Short: "Search tasks by keyword",
-	Args:  cobra.ExactArgs(1),
+	Use:   "search [query]...",
+	Short: "Search tasks by keyword or query",
+	Long: "Search tasks. Plain words and quoted phrases match the name or content; field predicates such as " +
+		"status:open, due<eow or project:work, AND, OR, NOT (or '-') and parentheses narrow the search further (see README). " +
+		"Results are ranked by relevance using the search index, " +
+		"and show the matching lines of each task with --context lines around them. " +
+		"With --regex the arguments are a regular expression, matched against the parts of the task --scope selects; " +
+		"with --fuzzy they are words that may be misspelled",
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !taskMode {
 			return fmt.Errorf("this command is only available for tasks, use 'note search' instead")
 		}
+		if err := checkScope(cmd, taskSearchScope, taskSearchRegex); err != nil {
+			return err
+		}
 		tm, err := tasks.NewTaskManager()
 		if err != nil {
 			return err
 		}
 
-		keyword := args[0]
-		taskList, err := tm.SearchTasks(keyword)
-		if err != nil {
-			return err
+		keyword := strings.Join(args, " ")
+		filter := tasks.Filter{Tags: taskSearchTags, NotTags: taskSearchNotTags}
+
+		var results []tasks.Result
+		var match snippet.Matcher
+		formatScore := func(score float64) string { return fmt.Sprintf("Score: %.2f", score) }
+		switch {
+		case taskSearchRegex:
+			re, err := regexp.Compile(keyword)
+			if err != nil {
+				return fmt.Errorf("invalid regular expression: %w", err)
+			}
+			if results, err = tm.SearchTasksRegex(re, tasks.Scope(taskSearchScope), filter); err != nil {
+				return err
+			}
+			if tasks.Scope(taskSearchScope) != tasks.ScopeName {
+				match = snippet.Regexp(re)
+			}
+			formatScore = func(score float64) string { return fmt.Sprintf("Matches: %.0f", score) }
+		case taskSearchFuzzy:
+			if results, err = tm.SearchTasksFuzzy(keyword, filter); err != nil {
+				return err
+			}
+			match = fuzzyMatcher(keyword)
+		default:
+			if results, err = tm.SearchTasks(keyword, filter); err != nil {
+				return err
+			}
+			words, err := tm.SearchWords(keyword)
+			if err != nil {
+				return err
+			}
+			match = snippet.Words(words)
+		}
+
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, results, taskResultColumns)
+		}
+
+		if taskSearchFormat != "" {
+			tmpl, err := loadTemplate(taskSearchFormat, taskTemplates())
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, result := range results {
+				if err := printTemplate(tmpl, newTaskTemplateData(&result.Task, result.Score, now)); err != nil {
+					return err
+				}
+			}
+			return nil
 		}
 
-		if len(taskList) == 0 {
+		if len(results) == 0 {
 			fmt.Printf("No tasks found matching '%s'\n", keyword)
 			return nil
 		}
 
-		for _, task := range taskList {
-			fmt.Printf("%s | %s | [%s] | Note: %s | Created: %s | Updated: %s\n",
+		for _, result := range results {
+			task := result.Task
+			fmt.Printf("%s | %s | [%s]%s | Note: %s | Created: %s | Updated: %s | %s\n",
 				task.ID,
 				task.Name,
 				task.Status,
+				formatTagList(task.Tags),
 				task.NoteID,
 				task.CreatedAt.Format("2006-01-02 15:04:05"),
-				task.UpdatedAt.Format("2006-01-02 15:04:05"))
+				task.UpdatedAt.Format("2006-01-02 15:04:05"),
+				formatScore(result.Score))
+			if match != nil {
+				printSnippets(task.Content, match, taskSearchContext)
+			}
 		}
 
 		return nil
@@ -47,6 +125,15 @@ var taskSearchCmd = &cobra.Command{
 
 func init() {
 	if taskMode {
-		rootCmd.AddCommand(taskSearchCmd)
+		taskSearchCmd.Flags().StringSliceVar(&taskSearchTags, "tag", nil, "Only show tasks with this tag (repeatable)")
+		taskSearchCmd.Flags().StringSliceVar(&taskSearchNotTags, "not-tag", nil, "Hide tasks with this tag (repeatable)")
+		taskSearchCmd.Flags().IntVarP(&taskSearchContext, "context", "C", 1, "Lines of context to show around each match")
+		taskSearchCmd.Flags().BoolVarP(&taskSearchRegex, "regex", "e", false, "Treat the query as a regular expression (RE2 syntax)")
+		taskSearchCmd.Flags().BoolVar(&taskSearchFuzzy, "fuzzy", false, "Match words with a few typos")
+		taskSearchCmd.Flags().StringVar(&taskSearchScope, "scope", string(tasks.ScopeBoth), "What --regex matches: name, content or both")
+		taskSearchCmd.Flags().StringVar(&taskSearchFormat, "format", "",
+			"Print each task with a Go template, or a named template ("+templateNames(taskTemplates())+"), instead of snippets")
+		taskSearchCmd.MarkFlagsMutuallyExclusive("regex", "fuzzy")
+		rootCmd.AddCommand(supportsOutput(taskSearchCmd))
 	}
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskShowRevision int
+
+var taskShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show a task",
+	Long:  "Show a task. Use --rev to show an earlier revision from the task's history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note show' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		var task *tasks.Task
+		if taskShowRevision > 0 {
+			task, err = tm.GetRevision(id, taskShowRevision)
+			if err != nil {
+				return exitWith(cmd, exitNotFound, "Revision %d of task %s not found", taskShowRevision, id)
+			}
+		} else {
+			task, err = tm.GetTask(id)
+			if err != nil {
+				return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+			}
+		}
+
+		if machineOutput() {
+			return output.One(os.Stdout, outputFormat, *task, taskOutputColumns)
+		}
+
+		fmt.Printf("ID: %s\n", task.ID)
+		if taskShowRevision > 0 {
+			fmt.Printf("Revision: %d\n", taskShowRevision)
+		}
+		fmt.Printf("Name: %s\n", task.Name)
+		fmt.Printf("Status: %s\n", task.Status)
+		if task.NoteID != "" {
+			fmt.Printf("Note: %s%s\n", task.NoteID, linkedNoteName(task.NoteID))
+		}
+		if len(task.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(task.Tags, ", "))
+		}
+		if task.ParentID != "" {
+			fmt.Printf("Parent: %s\n", task.ParentID)
+		}
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("Depends on: %s\n", strings.Join(task.DependsOn, ", "))
+		}
+		if task.Recur != "" {
+			fmt.Printf("Recur: %s\n", task.Recur)
+		}
+		if task.SeriesID != "" && task.SeriesID != task.ID {
+			fmt.Printf("Series: %s\n", task.SeriesID)
+		}
+		if task.Project != "" {
+			fmt.Printf("Project: %s\n", task.Project)
+		}
+		if task.Priority != tasks.PriorityNone {
+			fmt.Printf("Priority: %s\n", task.Priority)
+		}
+		if task.DueAt != nil {
+			overdue := ""
+			if task.IsOverdue(time.Now()) {
+				overdue = " (OVERDUE)"
+			}
+			fmt.Printf("Due: %s%s\n", formatDate(*task.DueAt), overdue)
+		}
+		if task.ScheduledAt != nil {
+			fmt.Printf("Scheduled: %s\n", formatDate(*task.ScheduledAt))
+		}
+		if task.Estimate > 0 || len(task.TimeLog) > 0 || task.StartedAt != nil {
+			fmt.Printf("Tracked: %s\n", formatTracked(task, time.Now()))
+		}
+		if task.StartedAt != nil {
+			fmt.Printf("Timer running since: %s\n", task.StartedAt.Format("2006-01-02 15:04:05"))
+		}
+		if !task.IsTerminal() {
+			fmt.Printf("Urgency: %.2f\n", task.Urgency(time.Now()))
+		}
+		fmt.Printf("Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Updated: %s\n", task.UpdatedAt.Format("2006-01-02 15:04:05"))
+		if len(task.Transitions) > 0 {
+			fmt.Println("Status history:")
+			for _, tr := range task.Transitions {
+				fmt.Printf("  %s\n", formatTransition(tr))
+			}
+		}
+		fmt.Println()
+		fmt.Println(task.Content)
+		return nil
+	},
+}
+
+// linkedNoteName returns " (name)" for an existing note, or " (missing)".
+func linkedNoteName(id string) string {
+	nm, err := notes.NewNoteManager()
+	if err != nil {
+		return ""
+	}
+	note, err := nm.GetNote(id)
+	if err != nil {
+		return " (missing)"
+	}
+	return fmt.Sprintf(" (%s)", note.Name)
+}
+
+func init() {
+	if taskMode {
+		taskShowCmd.Flags().IntVarP(&taskShowRevision, "rev", "r", 0, "Show revision N from the task's history")
+		rootCmd.AddCommand(supportsOutput(taskShowCmd))
+	}
+}
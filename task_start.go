@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskStartCmd = &cobra.Command{
+	Use:   "start [id]",
+	Short: "Start a timer on a task",
+	Long:  "Start timing work on a task. Only one timer can run at a time; stop it with 'task stop'",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		task, err := tm.Start(id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Timer started on task %s (%s)\n", task.ID, task.Name)
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskStartCmd)
+	}
+}
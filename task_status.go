@@ -2,16 +2,35 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/output"
 	"github.com/wltechblog/notes/internal/tasks"
 )
 
+var (
+	taskStatusCascade bool
+	taskStatusMessage string
+)
+
 var taskStatusCmd = &cobra.Command{
 	Use:   "status [id] [status]",
-	Short: "Change task status (open, completed, abandoned)",
-	Long:  "Change task status. Valid statuses: open, completed, abandoned",
+	Short: "Change task status",
 	Args:  cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		workflow := tasks.CurrentWorkflow()
+		statuses := workflow.Names()
+		if tm, err := tasks.NewTaskManager(); err == nil {
+			if task, err := tm.GetTask(args[0]); err == nil {
+				statuses = workflow.Targets(task.Status)
+			}
+		}
+		return statusNames(statuses), cobra.ShellCompDirectiveNoFileComp
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !taskMode {
 			return fmt.Errorf("this command is only available for tasks")
@@ -23,26 +42,108 @@ var taskStatusCmd = &cobra.Command{
 
 		id := args[0]
 		status := tasks.Status(args[1])
+		workflow := tasks.CurrentWorkflow()
+
+		if !workflow.Valid(status) {
+			return fmt.Errorf("invalid status: %s (must be one of: %s)", status, workflow.Describe())
+		}
 
-		switch status {
-		case tasks.StatusOpen, tasks.StatusCompleted, tasks.StatusAbandoned:
-		default:
-			return fmt.Errorf("invalid status: %s (must be: open, completed, or abandoned)", status)
+		if _, err := tm.GetTask(id); err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
 		}
 
-		_, err = tm.UpdateTaskStatus(id, status)
+		var openSubtasks []tasks.Task
+		if status == workflow.Completed() {
+			if openSubtasks, err = tm.OpenDescendants(id); err != nil {
+				return err
+			}
+		}
+		// Check every cascaded move up front so a subtask the workflow
+		// will not let through leaves the whole tree untouched.
+		if taskStatusCascade {
+			for _, subtask := range openSubtasks {
+				if !workflow.CanTransition(subtask.Status, status) {
+					return exitWith(cmd, exitError, "cannot cascade to subtask %s (%s): it cannot move from %s to %s",
+						subtask.ID, subtask.Name, subtask.Status, status)
+				}
+			}
+		}
+
+		update, err := tm.UpdateTaskStatus(id, status, taskStatusMessage)
 		if err != nil {
-			fmt.Printf("Task not found: %s\n", id)
-			return nil
+			return err
 		}
 
-		fmt.Printf("Task %s status updated to: %s\n", id, status)
-		return nil
+		changes := []statusChange{handleStatusUpdate(update)}
+		for _, subtask := range openSubtasks {
+			if !taskStatusCascade {
+				notice("Warning: subtask %s (%s) is still open\n", subtask.ID, subtask.Name)
+				continue
+			}
+			update, err := tm.UpdateTaskStatus(subtask.ID, status, fmt.Sprintf("cascaded from task %s", id))
+			if err != nil {
+				// Report what was already applied before giving up.
+				if outErr := printStatusChanges(changes); outErr != nil {
+					return outErr
+				}
+				return err
+			}
+			changes = append(changes, handleStatusUpdate(update))
+		}
+		if len(openSubtasks) > 0 && !taskStatusCascade {
+			notice("Use --cascade to complete open subtasks along with their parent\n")
+		}
+
+		return printStatusChanges(changes)
 	},
 }
 
+// printStatusChanges writes the applied status changes with machine-readable
+// output; in text mode handleStatusUpdate has already reported them.
+func printStatusChanges(changes []statusChange) error {
+	if machineOutput() {
+		return output.List(os.Stdout, outputFormat, changes, statusChangeColumns)
+	}
+	return nil
+}
+
+// handleStatusUpdate ticks or clears the task's checklist item in its note and
+// reports the status change and its side effects, returning them as the
+// record written with machine-readable output.
+func handleStatusUpdate(update *tasks.StatusUpdate) statusChange {
+	task := update.Task
+	change := statusChange{ID: task.ID, Name: task.Name, Status: string(task.Status), Unblocked: []string{}}
+	if tr := task.LastTransition(task.Status); tr != nil {
+		change.From = string(tr.From)
+	}
+	notice("Task %s status updated to: %s\n", task.ID, task.Status)
+
+	if err := syncTaskToChecklist(task); err != nil {
+		notice("Warning: could not update checklist: %v\n", err)
+	}
+	if next := update.Next; next != nil {
+		change.NextID = next.ID
+		due := ""
+		if next.DueAt != nil {
+			due = fmt.Sprintf(", due %s", formatDate(*next.DueAt))
+		}
+		notice("Next occurrence created: %s%s\n", next.ID, due)
+	}
+	for _, unblocked := range update.Unblocked {
+		change.Unblocked = append(change.Unblocked, unblocked.ID)
+		notice("Task %s (%s) is now unblocked\n", unblocked.ID, unblocked.Name)
+	}
+	return change
+}
+
 func init() {
 	if taskMode {
-		rootCmd.AddCommand(taskStatusCmd)
+		workflow := tasks.CurrentWorkflow()
+		taskStatusCmd.Long = fmt.Sprintf("Change task status. Valid statuses: %s. "+
+			"Moving a task to %s with unfinished subtasks prints a warning unless --cascade is given, which moves them too",
+			workflow.Describe(), workflow.Completed())
+		taskStatusCmd.Flags().StringVarP(&taskStatusMessage, "message", "m", "", "Comment to record with the status change")
+		taskStatusCmd.Flags().BoolVar(&taskStatusCascade, "cascade", false, "Also complete any unfinished subtasks")
+		rootCmd.AddCommand(supportsOutput(taskStatusCmd))
 	}
 }
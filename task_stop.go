@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskStopCmd = &cobra.Command{
+	Use:   "stop [id]",
+	Short: "Stop the running timer",
+	Long:  "Stop the running timer and log the elapsed time on its task. The ID is optional since only one timer can run",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		var id string
+		if len(args) > 0 {
+			id = args[0]
+			if _, err := tm.GetTask(id); err != nil {
+				return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+			}
+		}
+
+		task, entry, err := tm.Stop(id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Timer stopped on task %s (%s): %s logged, %s in total\n",
+			task.ID, task.Name, formatDuration(entry.Duration), formatDuration(task.Tracked(time.Now())))
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskStopCmd)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskTagCmd = &cobra.Command{
+	Use:   "tag [id] [add|remove] [tag]...",
+	Short: "Add or remove tags on a task",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note tag' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id, action := args[0], args[1]
+		tagList, err := parseTagArgs(args[2:])
+		if err != nil {
+			return err
+		}
+
+		var task *tasks.Task
+		switch action {
+		case "add":
+			task, err = tm.AddTags(id, tagList...)
+		case "remove", "rm":
+			task, err = tm.RemoveTags(id, tagList...)
+		default:
+			return fmt.Errorf("invalid action: %s (must be: add or remove)", action)
+		}
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "Task not found: %s", id)
+		}
+
+		fmt.Printf("Task %s tags: %s\n", id, strings.Join(task.Tags, ", "))
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		rootCmd.AddCommand(taskTagCmd)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var (
+	taskTimeSince   string
+	taskTimeUntil   string
+	taskTimeProject string
+	taskTimeBy      string
+)
+
+var taskTimeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Report tracked time",
+	Long: "Total tracked time per task, day or tag. Use --since/--until to limit the period and --project to " +
+		"limit it to a project and its sub-projects. Per-task totals are compared against estimates",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		filter := tasks.TimeFilter{Project: taskTimeProject}
+		if taskTimeSince != "" {
+			if filter.Since, err = parseDate(taskTimeSince); err != nil {
+				return err
+			}
+		}
+		if taskTimeUntil != "" {
+			if filter.Until, err = parseDate(taskTimeUntil); err != nil {
+				return err
+			}
+		}
+
+		report, err := tm.TimeReport(filter, taskTimeBy)
+		if err != nil {
+			return err
+		}
+
+		if len(report) == 0 {
+			fmt.Println("No time tracked")
+			return nil
+		}
+
+		var total time.Duration
+		for _, bucket := range report {
+			total += bucket.Duration
+			switch {
+			case bucket.Task != nil:
+				line := fmt.Sprintf("%s | %s | %s", bucket.Task.ID, bucket.Task.Name, formatDuration(bucket.Duration))
+				// The estimate covers the whole task, so within a period
+				// the comparison is labelled as all-time tracking.
+				if bucket.Task.Estimate > 0 {
+					label := "Tracked"
+					if !filter.Since.IsZero() || !filter.Until.IsZero() {
+						label = "All time"
+					}
+					line += fmt.Sprintf(" | %s: %s", label, formatTracked(bucket.Task, time.Now()))
+				}
+				fmt.Println(line)
+			case bucket.Key == "" && taskTimeBy == "tag":
+				fmt.Printf("(untagged) | %s\n", formatDuration(bucket.Duration))
+			default:
+				fmt.Printf("%s | %s\n", bucket.Key, formatDuration(bucket.Duration))
+			}
+		}
+		if taskTimeBy != "tag" {
+			fmt.Printf("Total: %s\n", formatDuration(total))
+		}
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		taskTimeCmd.Flags().StringVar(&taskTimeSince, "since", "", "Only count time started at or after this date")
+		taskTimeCmd.Flags().StringVar(&taskTimeUntil, "until", "", "Only count time started before this date")
+		taskTimeCmd.Flags().StringVar(&taskTimeProject, "project", "", "Only count tasks in this project or its sub-projects")
+		taskTimeCmd.Flags().StringVar(&taskTimeBy, "by", "task", "Group by "+strings.Join(tasks.TimeGroups, ", "))
+		rootCmd.AddCommand(taskTimeCmd)
+	}
+}
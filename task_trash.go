@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/dateparse"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/storage"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+var taskTrashOlderThan string
+
+var taskTrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted tasks",
+}
+
+var taskTrashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List deleted tasks",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note trash list' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		trashed, err := tm.ListTrash()
+		if err != nil {
+			return err
+		}
+
+		if len(trashed) == 0 {
+			fmt.Println("Trash is empty")
+			return nil
+		}
+
+		for _, task := range trashed {
+			fmt.Printf("%s | %s | [%s] | Deleted: %s\n",
+				task.ID,
+				task.Name,
+				task.Status,
+				task.DeletedAt.Format("2006-01-02 15:04:05"))
+		}
+
+		return nil
+	},
+}
+
+var taskTrashRestoreCmd = &cobra.Command{
+	Use:   "restore [id]",
+	Short: "Restore a deleted task",
+	Long:  "Restore a deleted task. If its linked note was deleted along with it, the note is restored too",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note trash restore' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		entry, err := tm.UndeleteTask(id)
+		if errors.Is(err, storage.ErrNotFound) {
+			return exitWith(cmd, exitNotFound, "Task not found in trash: %s", id)
+		}
+		if err != nil {
+			return exitWith(cmd, exitError, "Failed to restore task: %v", err)
+		}
+
+		if entry.NoteTrashed && entry.NoteID != "" {
+			nm, err := notes.NewNoteManager()
+			if err != nil {
+				return err
+			}
+			if _, err := nm.GetNote(entry.NoteID); err != nil {
+				if _, err := nm.UndeleteNote(entry.NoteID); err == nil {
+					fmt.Printf("Note restored: %s\n", entry.NoteID)
+				}
+			}
+		}
+
+		fmt.Printf("Task restored: %s\n", id)
+		return nil
+	},
+}
+
+var taskTrashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently remove deleted tasks",
+	Long:  "Permanently remove deleted tasks. Use --older-than (e.g. 30d, 2w, 12h) to keep recent deletions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note trash empty' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		var olderThan time.Duration
+		if taskTrashOlderThan != "" {
+			if olderThan, err = dateparse.ParseDuration(taskTrashOlderThan); err != nil {
+				return err
+			}
+		}
+
+		removed, err := tm.EmptyTrash(olderThan)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Permanently removed %d task(s)\n", len(removed))
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		taskTrashEmptyCmd.Flags().StringVar(&taskTrashOlderThan, "older-than", "", "Only remove tasks deleted longer ago than this (e.g. 30d)")
+		taskTrashCmd.AddCommand(taskTrashListCmd, taskTrashRestoreCmd, taskTrashEmptyCmd)
+		rootCmd.AddCommand(taskTrashCmd)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/tasks"
+	"github.com/wltechblog/notes/internal/views"
+)
+
+var (
+	taskViewSort    string
+	taskViewColumns string
+)
+
+var taskViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Manage saved task views",
+	Long:  "Saved views are named queries with an optional sort order and column list, kept in the tasks directory",
+}
+
+var taskViewSaveCmd = &cobra.Command{
+	Use:   "save [name] [query]...",
+	Short: "Save a query as a named view",
+	Long: "Save a query as a named view, replacing any view with the same name, e.g. " +
+		"'task view save this-week status:open due<=eow --sort due'. " +
+		"--sort sets the order (" + strings.Join(tasks.SortKeys, ", ") + ") and " +
+		"--columns picks the output columns (" + strings.Join(columnNames(taskColumns), ", ") + ")",
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note view save' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		view := &views.View{Name: args[0], Query: strings.Join(args[1:], " "), Sort: taskViewSort}
+		if _, err := tasks.ParseQuery(view.Query); err != nil {
+			return err
+		}
+		if err := tasks.SortTasks(nil, view.Sort, time.Now()); err != nil {
+			return err
+		}
+		if view.Columns, err = parseColumns(taskViewColumns, taskColumns); err != nil {
+			return err
+		}
+
+		if err := tm.Views().Save(view); err != nil {
+			return err
+		}
+
+		fmt.Printf("View saved: %s\n", view.Name)
+		return nil
+	},
+}
+
+var taskViewListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List saved task views",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note view list' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		list, err := tm.Views().List()
+		if err != nil {
+			return err
+		}
+
+		if len(list) == 0 {
+			fmt.Println("No views saved")
+			return nil
+		}
+
+		for _, view := range list {
+			fmt.Println(formatView(&view))
+		}
+		return nil
+	},
+}
+
+var taskViewRunCmd = &cobra.Command{
+	Use:   "run [name]",
+	Short: "List the tasks matching a saved view",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note view run' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		view, err := tm.Views().Get(args[0])
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "View not found: %s", args[0])
+		}
+
+		q, err := tasks.ParseQuery(view.Query)
+		if err != nil {
+			return err
+		}
+
+		taskList, err := tm.ListTasks(tasks.Filter{Query: q})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tasks.SortTasks(taskList, view.Sort, now); err != nil {
+			return err
+		}
+
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, taskList, taskOutputColumns)
+		}
+
+		if len(taskList) == 0 {
+			fmt.Println("No tasks found")
+			return nil
+		}
+
+		for _, task := range taskList {
+			if len(view.Columns) > 0 {
+				fmt.Println(formatTaskColumns(&task, view.Columns, now))
+			} else {
+				fmt.Println(formatTaskLine(&task, "", now))
+			}
+		}
+		return nil
+	},
+}
+
+var taskViewDeleteCmd = &cobra.Command{
+	Use:     "delete [name]",
+	Aliases: []string{"del", "rm"},
+	Short:   "Delete a saved task view",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !taskMode {
+			return fmt.Errorf("this command is only available for tasks, use 'note view delete' instead")
+		}
+		tm, err := tasks.NewTaskManager()
+		if err != nil {
+			return err
+		}
+
+		if err := tm.Views().Delete(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("View deleted: %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	if taskMode {
+		taskViewSaveCmd.Flags().StringVar(&taskViewSort, "sort", "", "Sort by "+strings.Join(tasks.SortKeys, ", "))
+		taskViewSaveCmd.Flags().StringVar(&taskViewColumns, "columns", "", "Comma-separated columns to show")
+		taskViewCmd.AddCommand(taskViewSaveCmd)
+		taskViewCmd.AddCommand(taskViewListCmd)
+		taskViewCmd.AddCommand(supportsOutput(taskViewRunCmd))
+		taskViewCmd.AddCommand(taskViewDeleteCmd)
+		rootCmd.AddCommand(taskViewCmd)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/wltechblog/notes/internal/config"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/tasks"
+	"github.com/wltechblog/notes/internal/templates"
+)
+
+// builtinNoteTemplates and builtinTaskTemplates can be used with --format
+// without any configuration. Templates of the same name in the config file
+// replace them.
+var builtinNoteTemplates = map[string]string{
+	"preview": "{{.ID}} | {{.Name}} | {{trunc 30 .Content}}",
+}
+
+var builtinTaskTemplates = map[string]string{
+	"preview": "{{.ID}} | {{.Name}} | [{{.Status}}] | {{trunc 30 .Content}}",
+}
+
+// noteTemplateData is what a --format template sees for a note.
+type noteTemplateData struct {
+	notes.Note
+	// Score is the search score, or 0 in lists.
+	Score float64
+}
+
+// taskTemplateData is what a --format template sees for a task, with the
+// values the text output computes.
+type taskTemplateData struct {
+	tasks.Task
+	Urgency float64
+	Overdue bool
+	Tracked string
+	Score   float64
+}
+
+func newTaskTemplateData(task *tasks.Task, score float64, now time.Time) taskTemplateData {
+	return taskTemplateData{
+		Task:    *task,
+		Urgency: task.Urgency(now),
+		Overdue: task.IsOverdue(now),
+		Tracked: formatTracked(task, now),
+		Score:   score,
+	}
+}
+
+func noteTemplates() map[string]string {
+	named := make(map[string]string)
+	for name, text := range builtinNoteTemplates {
+		named[name] = text
+	}
+	if appConfig.Templates != nil {
+		for name, text := range appConfig.Templates.Notes {
+			named[name] = text
+		}
+	}
+	return named
+}
+
+func taskTemplates() map[string]string {
+	named := make(map[string]string)
+	for name, text := range builtinTaskTemplates {
+		named[name] = text
+	}
+	if appConfig.Templates != nil {
+		for name, text := range appConfig.Templates.Tasks {
+			named[name] = text
+		}
+	}
+	return named
+}
+
+// loadTemplate resolves a --format value: the name of a template in named,
+// or else the text of a template.
+func loadTemplate(format string, named map[string]string) (*template.Template, error) {
+	if text, ok := named[format]; ok {
+		tmpl, err := templates.Parse(format, text, useColor())
+		if err != nil {
+			path, _ := config.Path()
+			return nil, fmt.Errorf("invalid template %q (built in or in %s): %w", format, path, err)
+		}
+		return tmpl, nil
+	}
+	tmpl, err := templates.Parse("format", format, useColor())
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// printTemplate renders data with tmpl as one line of output.
+func printTemplate(tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	out := buf.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err := os.Stdout.WriteString(out)
+	return err
+}
+
+// templateNames lists the named templates for flag help.
+func templateNames(named map[string]string) string {
+	return strings.Join(columnNames(named), ", ")
+}
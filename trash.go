@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/dateparse"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/storage"
+)
+
+var trashOlderThan string
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted notes",
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List deleted notes",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task trash list' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		trashed, err := nm.ListTrash()
+		if err != nil {
+			return err
+		}
+
+		if len(trashed) == 0 {
+			fmt.Println("Trash is empty")
+			return nil
+		}
+
+		for _, note := range trashed {
+			fmt.Printf("%s | %s | Deleted: %s\n",
+				note.ID,
+				note.Name,
+				note.DeletedAt.Format("2006-01-02 15:04:05"))
+		}
+
+		return nil
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore [id]",
+	Short: "Restore a deleted note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task trash restore' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		id := args[0]
+		_, err = nm.UndeleteNote(id)
+		if errors.Is(err, storage.ErrNotFound) {
+			return exitWith(cmd, exitNotFound, "Note not found in trash: %s", id)
+		}
+		if err != nil {
+			return exitWith(cmd, exitError, "Failed to restore note: %v", err)
+		}
+
+		fmt.Printf("Note restored: %s\n", id)
+		return nil
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently remove deleted notes",
+	Long:  "Permanently remove deleted notes. Use --older-than (e.g. 30d, 2w, 12h) to keep recent deletions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task trash empty' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		var olderThan time.Duration
+		if trashOlderThan != "" {
+			if olderThan, err = dateparse.ParseDuration(trashOlderThan); err != nil {
+				return err
+			}
+		}
+
+		removed, err := nm.EmptyTrash(olderThan)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Permanently removed %d note(s)\n", len(removed))
+		return nil
+	},
+}
+
+func init() {
+	if noteMode {
+		trashEmptyCmd.Flags().StringVar(&trashOlderThan, "older-than", "", "Only remove notes deleted longer ago than this (e.g. 30d)")
+		trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashEmptyCmd)
+		rootCmd.AddCommand(trashCmd)
+	}
+}
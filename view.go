@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/notes"
+	"github.com/wltechblog/notes/internal/output"
+	"github.com/wltechblog/notes/internal/views"
+)
+
+var (
+	viewSort    string
+	viewColumns string
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Manage saved note views",
+	Long:  "Saved views are named queries with an optional sort order and column list, kept in the notes directory",
+}
+
+var viewSaveCmd = &cobra.Command{
+	Use:   "save [name] [query]...",
+	Short: "Save a query as a named view",
+	Long: "Save a query as a named view, replacing any view with the same name. " +
+		"--sort sets the order (" + strings.Join(notes.SortKeys, ", ") + ") and " +
+		"--columns picks the output columns (" + strings.Join(columnNames(noteColumns), ", ") + ")",
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task view save' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		view := &views.View{Name: args[0], Query: strings.Join(args[1:], " "), Sort: viewSort}
+		if _, err := notes.ParseQuery(view.Query); err != nil {
+			return err
+		}
+		if err := notes.SortNotes(nil, view.Sort); err != nil {
+			return err
+		}
+		if view.Columns, err = parseColumns(viewColumns, noteColumns); err != nil {
+			return err
+		}
+
+		if err := nm.Views().Save(view); err != nil {
+			return err
+		}
+
+		fmt.Printf("View saved: %s\n", view.Name)
+		return nil
+	},
+}
+
+var viewListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List saved note views",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task view list' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		list, err := nm.Views().List()
+		if err != nil {
+			return err
+		}
+
+		if len(list) == 0 {
+			fmt.Println("No views saved")
+			return nil
+		}
+
+		for _, view := range list {
+			fmt.Println(formatView(&view))
+		}
+		return nil
+	},
+}
+
+var viewRunCmd = &cobra.Command{
+	Use:   "run [name]",
+	Short: "List the notes matching a saved view",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task view run' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		view, err := nm.Views().Get(args[0])
+		if err != nil {
+			return exitWith(cmd, exitNotFound, "View not found: %s", args[0])
+		}
+
+		q, err := notes.ParseQuery(view.Query)
+		if err != nil {
+			return err
+		}
+
+		notesList, err := nm.ListNotes(notes.Filter{Query: q})
+		if err != nil {
+			return err
+		}
+
+		if err := notes.SortNotes(notesList, view.Sort); err != nil {
+			return err
+		}
+
+		if machineOutput() {
+			return output.List(os.Stdout, outputFormat, notesList, noteOutputColumns)
+		}
+
+		if len(notesList) == 0 {
+			fmt.Println("No notes found")
+			return nil
+		}
+
+		for _, note := range notesList {
+			if len(view.Columns) > 0 {
+				fmt.Println(formatNoteColumns(&note, view.Columns))
+			} else {
+				fmt.Println(formatNoteLine(&note))
+			}
+		}
+		return nil
+	},
+}
+
+var viewDeleteCmd = &cobra.Command{
+	Use:     "delete [name]",
+	Aliases: []string{"del", "rm"},
+	Short:   "Delete a saved note view",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !noteMode {
+			return fmt.Errorf("this command is only available for notes, use 'task view delete' instead")
+		}
+		nm, err := notes.NewNoteManager()
+		if err != nil {
+			return err
+		}
+
+		if err := nm.Views().Delete(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("View deleted: %s\n", args[0])
+		return nil
+	},
+}
+
+func formatView(view *views.View) string {
+	line := fmt.Sprintf("%s | %s", view.Name, view.Query)
+	if view.Sort != "" {
+		line += fmt.Sprintf(" | Sort: %s", view.Sort)
+	}
+	if len(view.Columns) > 0 {
+		line += fmt.Sprintf(" | Columns: %s", strings.Join(view.Columns, ","))
+	}
+	return line
+}
+
+func init() {
+	if noteMode {
+		viewSaveCmd.Flags().StringVar(&viewSort, "sort", "", "Sort by "+strings.Join(notes.SortKeys, ", "))
+		viewSaveCmd.Flags().StringVar(&viewColumns, "columns", "", "Comma-separated columns to show")
+		viewCmd.AddCommand(viewSaveCmd)
+		viewCmd.AddCommand(viewListCmd)
+		viewCmd.AddCommand(supportsOutput(viewRunCmd))
+		viewCmd.AddCommand(viewDeleteCmd)
+		rootCmd.AddCommand(viewCmd)
+	}
+}
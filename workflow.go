@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wltechblog/notes/internal/tasks"
+)
+
+func statusNames(statuses []tasks.Status) []string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return names
+}
+
+func completeStatuses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return statusNames(tasks.CurrentWorkflow().Names()), cobra.ShellCompDirectiveNoFileComp
+}